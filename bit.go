@@ -0,0 +1,166 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isBitValue returns whether the Go type rt can hold the content of a bit (X) column:
+// bool, []bool, [N]bool or an unsigned integer used as a packed bitset.
+func isBitValue(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Bool,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Slice, reflect.Array:
+		return rt.Elem().Kind() == reflect.Bool
+	}
+	return false
+}
+
+// readBits reads the bits of the X column icol at row irow into ptr.
+// The first bit of the column is stored in the most significant bit of packed bitsets.
+// icol and irow are 0-based indices.
+func (col *Column) readBits(f *File, icol int, irow int64, ptr interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(ptr))
+	rt := reflect.TypeOf(rv.Interface())
+
+	nbits := col.Len
+	if nbits < 1 {
+		nbits = 1
+	}
+	err := checkBitValue(rt, nbits)
+	if err != nil {
+		return err
+	}
+
+	c_bits := make([]C.char, nbits)
+	c_status := C.int(0)
+	C.fits_read_col_bit(f.c, C.int(icol+1), C.LONGLONG(irow+1), 1, C.LONGLONG(nbits), &c_bits[0], &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	var value reflect.Value
+	switch rt.Kind() {
+	case reflect.Bool:
+		value = reflect.ValueOf(c_bits[0] != 0)
+	case reflect.Slice:
+		value = reflect.ValueOf(nullMask(c_bits))
+	case reflect.Array:
+		value = reflect.New(rt).Elem()
+		for i, c := range c_bits {
+			value.Index(i).SetBool(c != 0)
+		}
+	default:
+		bits := uint64(0)
+		size := uint(rt.Bits())
+		for i, c := range c_bits {
+			if c != 0 {
+				bits |= 1 << (size - 1 - uint(i))
+			}
+		}
+		value = reflect.New(rt).Elem()
+		value.SetUint(bits)
+	}
+	rv.Set(value)
+	col.Value = rv.Interface()
+	return nil
+}
+
+// writeBits writes value into the X column icol at row irow.
+// icol and irow are 0-based indices.
+func (col *Column) writeBits(f *File, icol int, irow int64, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	rt := rv.Type()
+
+	nbits := col.Len
+	if nbits < 1 {
+		nbits = 1
+	}
+	err := checkBitValue(rt, nbits)
+	if err != nil {
+		return err
+	}
+	if rt.Kind() == reflect.Slice && rv.Len() != nbits {
+		return fmt.Errorf("cfitsio: can not write %d bits into a %d bits column", rv.Len(), nbits)
+	}
+
+	c_bits := make([]C.char, nbits)
+	switch rt.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			c_bits[0] = 1
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range c_bits {
+			if rv.Index(i).Bool() {
+				c_bits[i] = 1
+			}
+		}
+	default:
+		bits := rv.Uint()
+		size := uint(rt.Bits())
+		for i := range c_bits {
+			if bits&(1<<(size-1-uint(i))) != 0 {
+				c_bits[i] = 1
+			}
+		}
+	}
+
+	c_status := C.int(0)
+	C.fits_write_col_bit(f.c, C.int(icol+1), C.LONGLONG(irow+1), 1, C.LONGLONG(nbits), &c_bits[0], &c_status)
+	return to_err(c_status)
+}
+
+// checkBitValue checks the Go type rt can hold nbits bits.
+func checkBitValue(rt reflect.Type, nbits int) error {
+	switch rt.Kind() {
+	case reflect.Bool:
+		if nbits == 1 {
+			return nil
+		}
+	case reflect.Slice:
+		return nil
+	case reflect.Array:
+		if rt.Len() == nbits {
+			return nil
+		}
+	default:
+		if rt.Bits() >= nbits {
+			return nil
+		}
+	}
+	return fmt.Errorf("cfitsio: can not hold %d bits in a [%v]", nbits, rt)
+}
+
+// bitsFormat returns the TFORM of a bit column holding values of Go type rt.
+// n is the number of bits, inferred from rt if 0.
+func bitsFormat(rt reflect.Type, n int) (string, error) {
+	if !isBitValue(rt) {
+		return "", fmt.Errorf("cfitsio: [%v] can not be stored in a bit column", rt)
+	}
+	if n == 0 {
+		switch rt.Kind() {
+		case reflect.Bool:
+			n = 1
+		case reflect.Array:
+			n = rt.Len()
+		case reflect.Slice:
+			return "", fmt.Errorf("cfitsio: number of bits of a [%v] bit column must be given", rt)
+		default:
+			n = rt.Bits()
+		}
+	}
+	err := checkBitValue(rt, n)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%dX", n), nil
+}
+
+// EOF
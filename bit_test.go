@@ -0,0 +1,139 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTableBits(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	type Quality struct {
+		Flags   uint32  `fits:"FLAGS,bits"`
+		Mask    [3]bool `fits:"MASK,bits"`
+		Bad     bool    `fits:"BAD,bits"`
+		Pattern []bool  `fits:"PATTERN,bits=5"`
+		Energy  float64 `fits:"ENERGY"`
+	}
+	data := []Quality{
+		{Flags: 0x80000001, Mask: [3]bool{true, false, true}, Bad: false, Pattern: []bool{true, true, false, false, true}, Energy: 1.5},
+		{Flags: 0x0000ff00, Mask: [3]bool{false, true, false}, Bad: true, Pattern: []bool{false, false, false, false, false}, Energy: 2.5},
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTableFrom(&f, "quality", &data[0], BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			for i, want := range []string{"32X", "3X", "1X", "5X", "D"} {
+				if got := tbl.Col(i).Format; got != want {
+					t.Fatalf("column %d: expected format %q. got %q", i, want, got)
+				}
+			}
+
+			for i := range data {
+				err = tbl.Write(&data[i])
+				if err != nil {
+					t.Fatalf("error writing row [%v]: %v", i, err)
+				}
+			}
+
+			type Invalid struct {
+				Pattern []bool `fits:"PATTERN,bits"`
+			}
+			_, err = NewTableFrom(&f, "invalid", Invalid{}, BINARY_TBL)
+			if err == nil {
+				t.Fatalf("expected an error creating a bit column of unknown size")
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			if tbl.Col(0).Type != TBIT || tbl.Col(0).Len != 32 {
+				t.Fatalf("expected a 32 bits column. got %v (len=%d)", tbl.Col(0).Type, tbl.Col(0).Len)
+			}
+
+			rows, err := tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			defer rows.Close()
+
+			i := 0
+			for rows.Next() {
+				var q Quality
+				err = rows.Scan(&q)
+				if err != nil {
+					t.Fatalf("error scanning row [%v]: %v", i, err)
+				}
+				if !reflect.DeepEqual(q, data[i]) {
+					t.Fatalf("row %d:\nexpected=%v\ngot=%v", i, data[i], q)
+				}
+
+				// the same bits, unpacked.
+				var flags []bool
+				err = tbl.Col(0).read(tbl.f, 0, int64(i), &flags)
+				if err != nil {
+					t.Fatalf("error reading bits: %v", err)
+				}
+				if len(flags) != 32 || flags[0] != (data[i].Flags>>31 == 1) || flags[31] != (data[i].Flags&1 == 1) {
+					t.Fatalf("row %d: unexpected bits %v", i, flags)
+				}
+
+				var small uint8
+				err = tbl.Col(0).read(tbl.f, 0, int64(i), &small)
+				if err == nil {
+					t.Fatalf("expected an error reading 32 bits into an uint8")
+				}
+				i++
+			}
+			if i != len(data) {
+				t.Fatalf("expected %d rows. got %d", len(data), i)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
@@ -54,8 +54,14 @@ func newCard(f *File, i int) (Card, error) {
 
 	keyclass := C.fits_get_keyclass(c_key)
 	switch keyclass {
-	case C.TYP_COMM_KEY, C.TYP_CONT_KEY:
-		return card, fmt.Errorf("comm key | continue key")
+	case C.TYP_CONT_KEY:
+		return card, fmt.Errorf("continue key")
+	case C.TYP_COMM_KEY:
+		// commentary keywords (COMMENT, HISTORY or blank keyword) have no value:
+		// their text is held in the comment field.
+		card.Name = name
+		card.Value = comment
+		return card, nil
 	}
 
 	err = parseRecord(name, value, comment, &card)
@@ -0,0 +1,192 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+)
+
+// ChecksumStatus is the verification status of a CHECKSUM or DATASUM keyword.
+type ChecksumStatus int
+
+const (
+	ChecksumMissing ChecksumStatus = 0  // the keyword is not present
+	ChecksumOK      ChecksumStatus = 1  // the keyword is present and correct
+	ChecksumBad     ChecksumStatus = -1 // the keyword is present but incorrect
+)
+
+func (st ChecksumStatus) String() string {
+	switch st {
+	case ChecksumMissing:
+		return "missing"
+	case ChecksumOK:
+		return "ok"
+	case ChecksumBad:
+		return "bad"
+	default:
+		panic(fmt.Errorf("invalid ChecksumStatus value (%v)", int(st)))
+	}
+}
+
+// Checksum is the result of the verification of the checksums of a HDU.
+// A bad DATASUM means the data unit was modified.
+// A bad CHECKSUM with a correct DATASUM means the header was modified.
+type Checksum struct {
+	HDU  ChecksumStatus // status of the CHECKSUM keyword, covering the whole HDU (header and data)
+	Data ChecksumStatus // status of the DATASUM keyword, covering the data unit only
+}
+
+// OK returns whether both the CHECKSUM and the DATASUM keywords are present and correct.
+func (sum Checksum) OK() bool {
+	return sum.HDU == ChecksumOK && sum.Data == ChecksumOK
+}
+
+// writeChecksum computes and writes the CHECKSUM and DATASUM keywords of the current HDU.
+func writeChecksum(f *File) error {
+	c_status := C.int(0)
+	C.fits_write_chksum(f.c, &c_status)
+	return to_err(c_status)
+}
+
+// verifyChecksum verifies the CHECKSUM and DATASUM keywords of the current HDU.
+func verifyChecksum(f *File) (Checksum, error) {
+	c_dataok := C.int(0)
+	c_hduok := C.int(0)
+	c_status := C.int(0)
+	C.fits_verify_chksum(f.c, &c_dataok, &c_hduok, &c_status)
+	if c_status > 0 {
+		return Checksum{}, to_err(c_status)
+	}
+	return Checksum{HDU: ChecksumStatus(c_hduok), Data: ChecksumStatus(c_dataok)}, nil
+}
+
+// WriteChecksum computes and writes the CHECKSUM and DATASUM keywords of this image,
+// in the file and in the cached Header.
+func (hdu *ImageHDU) WriteChecksum() error {
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+	err = writeChecksum(hdu.f)
+	if err != nil {
+		return err
+	}
+	return hdu.reloadHeader()
+}
+
+// VerifyChecksum verifies the CHECKSUM and DATASUM keywords of this image.
+func (hdu *ImageHDU) VerifyChecksum() (Checksum, error) {
+	err := hdu.seekHDU()
+	if err != nil {
+		return Checksum{}, err
+	}
+	return verifyChecksum(hdu.f)
+}
+
+// WriteChecksum computes and writes the CHECKSUM and DATASUM keywords of this table,
+// in the file and in the cached Header.
+func (hdu *Table) WriteChecksum() error {
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+	err = writeChecksum(hdu.f)
+	if err != nil {
+		return err
+	}
+	return hdu.reloadHeader()
+}
+
+// VerifyChecksum verifies the CHECKSUM and DATASUM keywords of this table.
+func (hdu *Table) VerifyChecksum() (Checksum, error) {
+	err := hdu.seekHDU()
+	if err != nil {
+		return Checksum{}, err
+	}
+	return verifyChecksum(hdu.f)
+}
+
+// checksummer is implemented by HDUs supporting checksums.
+type checksummer interface {
+	WriteChecksum() error
+	VerifyChecksum() (Checksum, error)
+}
+
+// WriteChecksums computes and writes the CHECKSUM and DATASUM keywords of all the HDUs of the file.
+func (f *File) WriteChecksums() error {
+	for i, hdu := range f.hdus {
+		sum, ok := hdu.(checksummer)
+		if !ok {
+			return fmt.Errorf("cfitsio: hdu #%d (%T) does not support checksums", i, hdu)
+		}
+		err := sum.WriteChecksum()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyChecksums verifies the CHECKSUM and DATASUM keywords of all the HDUs of the file.
+// The i-th Checksum is the result for the i-th HDU.
+func (f *File) VerifyChecksums() ([]Checksum, error) {
+	sums := make([]Checksum, 0, len(f.hdus))
+	for i, hdu := range f.hdus {
+		sum, ok := hdu.(checksummer)
+		if !ok {
+			return nil, fmt.Errorf("cfitsio: hdu #%d (%T) does not support checksums", i, hdu)
+		}
+		v, err := sum.VerifyChecksum()
+		if err != nil {
+			return nil, err
+		}
+		sums = append(sums, v)
+	}
+	return sums, nil
+}
+
+// SetChecksumOnClose enables or disables the automatic update of checksums when the file is closed.
+// When enabled and the file is writable, Close rewrites the CHECKSUM and DATASUM keywords
+// of the HDUs created or written since the file was opened.
+// The other HDUs are left as is: invalid checksums of untouched HDUs are reported by
+// VerifyChecksums and Verify, not fixed.
+func (f *File) SetChecksumOnClose(update bool) {
+	f.chksum = update
+}
+
+// pristineHDU is implemented by HDUs tracking whether they were written since the file was opened.
+type pristineHDU interface {
+	setPristine(v bool)
+	isPristine() bool
+}
+
+func (hdu *ImageHDU) setPristine(v bool) { hdu.pristine = v }
+func (hdu *ImageHDU) isPristine() bool   { return hdu.pristine }
+func (hdu *Table) setPristine(v bool)    { hdu.pristine = v }
+func (hdu *Table) isPristine() bool      { return hdu.pristine }
+
+// updateChecksums rewrites the checksums of the HDUs written since the file was opened.
+func (f *File) updateChecksums() error {
+	mode, err := f.Mode()
+	if err != nil || mode != ReadWrite {
+		return err
+	}
+	for i, hdu := range f.hdus {
+		if p, ok := hdu.(pristineHDU); ok && p.isPristine() {
+			continue
+		}
+		sum, ok := hdu.(checksummer)
+		if !ok {
+			return fmt.Errorf("cfitsio: hdu #%d (%T) does not support checksums", i, hdu)
+		}
+		err = sum.WriteChecksum()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EOF
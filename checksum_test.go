@@ -0,0 +1,164 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	ok := Checksum{HDU: ChecksumOK, Data: ChecksumOK}
+	verify := func(f *File, want []Checksum) {
+		sums, err := f.VerifyChecksums()
+		if err != nil {
+			t.Fatalf("error verifying checksums: %v", err)
+		}
+		if len(sums) != len(want) {
+			t.Fatalf("expected %d checksums. got %d", len(want), len(sums))
+		}
+		for i := range sums {
+			if sums[i] != want[i] {
+				t.Fatalf("hdu #%d: expected %+v. got %+v", i, want[i], sums[i])
+			}
+		}
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewHeader(nil, IMAGE_HDU, 16, []int64{4}))
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+			data := []int16{1, 2, 3, 4}
+			err = phdu.(*PrimaryHDU).Write(&data)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			tbl, err := NewTable(&f, "data", []Column{{Name: "X", Value: float64(0)}}, BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+			xs := []float64{1, 2, 3}
+			err = tbl.WriteColumn(0, 0, &xs)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+
+			verify(&f, []Checksum{{}, {}})
+
+			err = f.WriteChecksums()
+			if err != nil {
+				t.Fatalf("error writing checksums: %v", err)
+			}
+			for i, hdu := range f.HDUs() {
+				hdr := hdu.Header()
+				for _, n := range []string{"CHECKSUM", "DATASUM"} {
+					if hdr.Get(n) == nil {
+						t.Fatalf("hdu #%d: expected %s in cached header", i, n)
+					}
+				}
+			}
+			verify(&f, []Checksum{ok, ok})
+
+			// an image without checksums.
+			img, err := NewImageHDU(&f, NewHeader(nil, IMAGE_HDU, 16, []int64{4}))
+			if err != nil {
+				t.Fatalf("error creating image: %v", err)
+			}
+			err = img.Write(&data)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			verify(&f, []Checksum{ok, ok, {}})
+		},
+		// modify
+		func() {
+			f, err := Open(fname, ReadWrite)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+			f.SetChecksumOnClose(true)
+
+			err = f.HDU(0).(*PrimaryHDU).UpdateKey("OBSERVER", "me", "")
+			if err != nil {
+				t.Fatalf("error updating key: %v", err)
+			}
+			tbl := f.HDU(1).(*Table)
+			xs := []float64{-1}
+			err = tbl.WriteColumn(0, 1, &xs)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+
+			// a new table, without checksums yet.
+			tbl, err = NewTable(&f, "more", []Column{{Name: "Y", Value: float64(0)}}, BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			err = tbl.WriteColumn(0, 0, &xs)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+
+			verify(&f, []Checksum{
+				{HDU: ChecksumBad, Data: ChecksumOK},
+				{HDU: ChecksumBad, Data: ChecksumBad},
+				{},
+				{},
+			})
+		},
+		// read-back, after the update on close:
+		// the untouched image still has no checksums.
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			verify(&f, []Checksum{ok, ok, {}, ok})
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
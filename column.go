@@ -22,11 +22,16 @@ type Column struct {
 	Bscale  float64 // bscale value, corresponding to ``TSCAL`` keyword
 	Bzero   float64 // bzero value, corresponding to ``TZERO`` keyword
 	Display string  // display format, corresponding to ``TDISP`` keyword
-	Dim     []int64 // column dimension corresponding to ``TDIM`` keyword
+	Dim     []int64 // column dimension corresponding to ``TDIM`` keyword (inferred from [N][M]T values)
 	Start   int64   // column starting position, corresponding to ``TBCOL`` keyword
 	Type    TypeCode
 	Len     int   // repeat. if <= 1: scalar
 	Value   Value // value at current row
+
+	table *Table // table holding this column, if any
+	index int    // 0-based index of this column in table
+	width int    // width in characters of each string of a string column
+	raw   bool   // whether values are exchanged without Bscale/Bzero scaling
 }
 
 // inferFormat infers the FITS format associated with a Column, according to its HDUType and Go type.
@@ -62,6 +67,16 @@ func (col *Column) read(f *File, icol int, irow int64, ptr interface{}) error {
 	rv := reflect.Indirect(reflect.ValueOf(ptr))
 	rt := reflect.TypeOf(rv.Interface())
 
+	if col.Type == TBIT && isBitValue(rt) {
+		return col.readBits(f, icol, irow, ptr)
+	}
+	if col.Type == TSTRING && isStringValue(rt) {
+		return col.readStrings(f, icol, irow, ptr)
+	}
+	if isNestedArray(rt) {
+		return col.readArray(f, icol, irow, ptr)
+	}
+
 	decode := func(c_type C.int, c_len C.LONGLONG, rt reflect.Type, scalar bool) (interface{}, error) {
 		var value interface{}
 		c_status := C.int(0)
@@ -238,15 +253,6 @@ func (col *Column) read(f *File, icol int, irow int64, ptr interface{}) error {
 				value = v[0]
 			}
 
-		case reflect.String:
-			c_type = C.TSTRING
-			// FIXME: get correct maximum size from card
-			c_value := C.CStringN(C.FLEN_FILENAME)
-			defer C.free(unsafe.Pointer(c_value))
-			c_ptr := unsafe.Pointer(&c_value)
-			C.fits_read_col(f.c, c_type, c_icol, c_irow, 1, 1, c_ptr, c_ptr, &c_anynul, &c_status)
-			value = C.GoString(c_value)
-
 		default:
 			return value, fmt.Errorf("cfitsio: invalid type [%v]", rt)
 		}
@@ -273,8 +279,27 @@ func (col *Column) read(f *File, icol int, irow int64, ptr interface{}) error {
 		} else {
 			c_len = C.long(col.Len)
 		}
-		scalar := false
-		value, err = decode(c_type, C.LONGLONG(c_len), rt.Elem(), scalar)
+		switch {
+		case c_len == 0:
+			value = reflect.MakeSlice(rt, 0, 0).Interface()
+
+		case rt.Elem().Kind() == reflect.String:
+			// a variable-length character array holds a single string per row.
+			c_status := C.int(0)
+			c_anynul := C.int(0)
+			c_value := C.CStringN(C.int(c_len + 1))
+			defer C.free(unsafe.Pointer(c_value))
+			c_ptr := unsafe.Pointer(&c_value)
+			C.fits_read_col(f.c, C.TSTRING, c_icol, c_irow, 1, 1, nil, c_ptr, &c_anynul, &c_status)
+			if c_status > 0 {
+				return to_err(c_status)
+			}
+			value = []string{C.GoString(c_value)}
+
+		default:
+			scalar := false
+			value, err = decode(c_type, C.LONGLONG(c_len), rt.Elem(), scalar)
+		}
 
 		rv.Set(reflect.ValueOf(value))
 		col.Value = rv.Interface()
@@ -294,8 +319,7 @@ func (col *Column) read(f *File, icol int, irow int64, ptr interface{}) error {
 		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64,
-		reflect.Complex64, reflect.Complex128,
-		reflect.String:
+		reflect.Complex64, reflect.Complex128:
 
 		scalar := true
 		value, err = decode(c_type, C.LONGLONG(c_len), rt, scalar)
@@ -323,6 +347,16 @@ func (col *Column) write(f *File, icol int, irow int64, value interface{}) error
 	rv := reflect.ValueOf(value)
 	rt := reflect.TypeOf(value)
 
+	if col.Type == TBIT && isBitValue(rt) {
+		return col.writeBits(f, icol, irow, value)
+	}
+	if col.Type == TSTRING && isStringValue(rt) {
+		return col.writeStrings(f, icol, irow, value)
+	}
+	if isNestedArray(rt) {
+		return col.writeArray(f, icol, irow, value)
+	}
+
 	switch rt.Kind() {
 	case reflect.Bool:
 		c_type = C.TLOGICAL
@@ -425,13 +459,22 @@ func (col *Column) write(f *File, icol int, irow int64, value interface{}) error
 		C.fits_write_col(f.c, c_type, c_icol, c_irow, 1, 1, c_ptr, &c_status)
 
 	case reflect.Slice:
+		if col.Type < 0 && rv.Len() == 0 {
+			// nothing to put on the heap: just write an empty descriptor.
+			return writeEmptyDescriptor(f, c_icol, c_irow)
+		}
 		switch rt.Elem().Kind() {
 		case reflect.Bool:
 			c_type = C.TLOGICAL
 			value := value.([]bool)
-			slice := (*reflect.SliceHeader)((unsafe.Pointer(&value)))
-			c_ptr := unsafe.Pointer(slice.Data)
-			C.fits_write_col(f.c, c_type, c_icol, c_irow, 1, C.LONGLONG(slice.Len), c_ptr, &c_status)
+			c_value := make([]C.char, len(value))
+			for i, v := range value {
+				if v {
+					c_value[i] = 1
+				}
+			}
+			c_ptr := unsafe.Pointer(&c_value[0])
+			C.fits_write_col(f.c, c_type, c_icol, c_irow, 1, C.LONGLONG(len(c_value)), c_ptr, &c_status)
 
 		case reflect.Uint8:
 			c_type = C.TBYTE
@@ -530,6 +573,19 @@ func (col *Column) write(f *File, icol int, irow int64, value interface{}) error
 			slice := (*reflect.SliceHeader)((unsafe.Pointer(&value))) // FIXME: assume same bin-layout
 			c_ptr := unsafe.Pointer(slice.Data)
 			C.fits_write_col(f.c, c_type, c_icol, c_irow, 1, C.LONGLONG(slice.Len), c_ptr, &c_status)
+
+		case reflect.String:
+			// a variable-length character array holds a single string per row.
+			value := value.([]string)
+			if col.Type != TVLASTRING || len(value) != 1 {
+				return fmt.Errorf("cfitsio: can not write %d strings into a row of column %q", len(value), col.Name)
+			}
+			c_type = C.TSTRING
+			c_value := C.CString(value[0])
+			defer C.free(unsafe.Pointer(c_value))
+			c_ptr := unsafe.Pointer(&c_value)
+			C.fits_write_col(f.c, c_type, c_icol, c_irow, 1, 1, c_ptr, &c_status)
+
 		default:
 			panic(fmt.Errorf("unhandled type '%T'", value))
 		}
@@ -618,4 +674,79 @@ func (col *Column) write(f *File, icol int, irow int64, value interface{}) error
 	return err
 }
 
+// setScaling sets the scaling CFITSIO applies to the values of this column (number icol, 0-based):
+// none if the column is raw, Bscale and Bzero otherwise.
+func (col *Column) setScaling(f *File, icol int) error {
+	scale, zero := col.Bscale, col.Bzero
+	if col.raw {
+		scale, zero = 1, 0
+	}
+	if scale == 0 {
+		// not a column read from a table.
+		return nil
+	}
+	c_status := C.int(0)
+	C.fits_set_tscale(f.c, C.int(icol+1), C.double(scale), C.double(zero), &c_status)
+	return to_err(c_status)
+}
+
+// Descriptor describes the storage of a variable-length array in the heap of a binary table.
+type Descriptor struct {
+	Len    int64 // number of elements
+	Offset int64 // offset in bytes from the start of the heap
+}
+
+// Descriptors returns the descriptors of all the rows of this variable-length array column.
+func (col *Column) Descriptors() ([]Descriptor, error) {
+	hdu := col.table
+	if hdu == nil || hdu.f == nil {
+		return nil, fmt.Errorf("cfitsio: column %q is not attached to a table", col.Name)
+	}
+	if col.Type >= 0 {
+		return nil, fmt.Errorf("cfitsio: column %q is not a variable-length array column", col.Name)
+	}
+	nrows := hdu.NumRows()
+	if nrows == 0 {
+		return nil, nil
+	}
+
+	err := hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	c_lens := make([]C.LONGLONG, nrows)
+	c_offs := make([]C.LONGLONG, nrows)
+	c_status := C.int(0)
+	C.fits_read_descriptsll(hdu.f.c, C.int(col.index+1), 1, C.LONGLONG(nrows), &c_lens[0], &c_offs[0], &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	descs := make([]Descriptor, nrows)
+	for i := range descs {
+		descs[i] = Descriptor{Len: int64(c_lens[i]), Offset: int64(c_offs[i])}
+	}
+	return descs, nil
+}
+
+// writeEmptyDescriptor writes a zero-length descriptor at column c_icol and row c_irow (1-based),
+// appending rows to the table if needed.
+func writeEmptyDescriptor(f *File, c_icol C.int, c_irow C.LONGLONG) error {
+	c_status := C.int(0)
+	c_nrows := C.LONGLONG(0)
+	C.fits_get_num_rowsll(f.c, &c_nrows, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	if c_irow > c_nrows {
+		C.fits_insert_rows(f.c, c_nrows, c_irow-c_nrows, &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+	}
+	C.fits_write_descript(f.c, c_icol, c_irow, 0, 0, &c_status)
+	return to_err(c_status)
+}
+
 // EOF
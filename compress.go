@@ -0,0 +1,284 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// CompressionType is the algorithm used to compress the tiles of an image.
+type CompressionType int
+
+const (
+	NoCompression        CompressionType = C.NOCOMPRESS  // no compression
+	RiceCompression      CompressionType = C.RICE_1      // Rice algorithm
+	GzipCompression      CompressionType = C.GZIP_1      // GZIP algorithm
+	Gzip2Compression     CompressionType = C.GZIP_2      // GZIP algorithm, with bytes shuffled
+	PlioCompression      CompressionType = C.PLIO_1      // IRAF PLIO algorithm (integer images only)
+	HCompressCompression CompressionType = C.HCOMPRESS_1 // H-compress algorithm (2D images only)
+)
+
+func (ct CompressionType) String() string {
+	switch ct {
+	case NoCompression:
+		return "NOCOMPRESS"
+	case RiceCompression:
+		return "RICE_1"
+	case GzipCompression:
+		return "GZIP_1"
+	case Gzip2Compression:
+		return "GZIP_2"
+	case PlioCompression:
+		return "PLIO_1"
+	case HCompressCompression:
+		return "HCOMPRESS_1"
+	default:
+		panic(fmt.Errorf("invalid CompressionType value (%v)", int(ct)))
+	}
+}
+
+// DitherMethod is the dithering method used when quantizing floating point images.
+type DitherMethod int
+
+const (
+	DefaultDither      DitherMethod = 0                      // CFITSIO default (SubtractiveDither1)
+	NoDither           DitherMethod = C.NO_DITHER            // no dithering
+	SubtractiveDither1 DitherMethod = C.SUBTRACTIVE_DITHER_1 // subtractive dithering
+	SubtractiveDither2 DitherMethod = C.SUBTRACTIVE_DITHER_2 // subtractive dithering, preserving zero-valued pixels
+)
+
+// defaultQuantizeLevel is the CFITSIO default quantization level of floating point images.
+const defaultQuantizeLevel = 4.0
+
+// Compression describes how an image is tile-compressed.
+// Type must be set. Zero values of the other fields select the CFITSIO defaults.
+type Compression struct {
+	Type     CompressionType // compression algorithm (required)
+	Tile     []int64         // tile dimensions (default: one row of the image per tile)
+	Quantize float32         // quantization level for floating point images
+	Dither   DitherMethod    // dithering method for floating point images
+	Lossless bool            // do not quantize floating point images
+	Scale    float32         // H-compress scale factor
+	Smooth   bool            // H-compress smoothing, when uncompressing
+}
+
+// set installs the compression parameters c on file f.
+// They apply to the images subsequently created in f.
+func (c Compression) set(f *File) error {
+	if c.Type == 0 {
+		return fmt.Errorf("cfitsio: compression type not set")
+	}
+	c_status := C.int(0)
+	C.fits_set_compression_type(f.c, C.int(c.Type), &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	if c.Type == NoCompression {
+		return nil
+	}
+
+	if len(c.Tile) > 0 {
+		tile := make([]C.long, len(c.Tile))
+		for i, v := range c.Tile {
+			tile[i] = C.long(v)
+		}
+		C.fits_set_tile_dim(f.c, C.int(len(tile)), &tile[0], &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+	}
+
+	switch {
+	case c.Lossless:
+		C.fits_set_quantize_level(f.c, 0, &c_status)
+	case c.Quantize != 0:
+		C.fits_set_quantize_level(f.c, C.float(c.Quantize), &c_status)
+	}
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	if c.Dither != DefaultDither {
+		C.fits_set_quantize_method(f.c, C.int(c.Dither), &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+	}
+
+	if c.Type == HCompressCompression {
+		C.fits_set_hcomp_scale(f.c, C.float(c.Scale), &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+		c_smooth := C.int(0)
+		if c.Smooth {
+			c_smooth = 1
+		}
+		C.fits_set_hcomp_smooth(f.c, c_smooth, &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+	}
+	return nil
+}
+
+// reset disables compression for the images subsequently created in f,
+// and restores the CFITSIO defaults of all the parameters set installed.
+func (c Compression) reset(f *File) error {
+	c_status := C.int(0)
+	C.fits_set_compression_type(f.c, C.NOCOMPRESS, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	// one row of the image per tile.
+	tile := make([]C.long, C.MAX_COMPRESS_DIM)
+	for i := 1; i < len(tile); i++ {
+		tile[i] = 1
+	}
+	C.fits_set_tile_dim(f.c, C.int(len(tile)), &tile[0], &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	C.fits_set_quantize_level(f.c, defaultQuantizeLevel, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	C.fits_set_quantize_method(f.c, C.SUBTRACTIVE_DITHER_1, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	C.fits_set_hcomp_scale(f.c, 0, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	C.fits_set_hcomp_smooth(f.c, 0, &c_status)
+	return to_err(c_status)
+}
+
+// NewCompressedImageHDU creates a new tile-compressed image extension with Header hdr in File f.
+// If f is empty, CFITSIO first creates an empty Primary HDU.
+func NewCompressedImageHDU(f *File, hdr Header, c Compression) (*ImageHDU, error) {
+	var err error
+	var hdu *ImageHDU
+
+	mode, err := f.Mode()
+	if err != nil {
+		return hdu, err
+	}
+	if mode == ReadOnly {
+		return hdu, READONLY_FILE
+	}
+
+	// restore the defaults even if set failed half-way.
+	defer c.reset(f)
+	err = c.set(f)
+	if err != nil {
+		return hdu, err
+	}
+
+	naxes := len(hdr.axes)
+	c_naxes := C.int(naxes)
+	slice := (*reflect.SliceHeader)((unsafe.Pointer(&hdr.axes)))
+	c_axes := (*C.long)(unsafe.Pointer(slice.Data))
+	c_status := C.int(0)
+
+	C.fits_create_img(f.c, C.int(hdr.bitpix), c_naxes, c_axes, &c_status)
+	if c_status > 0 {
+		return hdu, to_err(c_status)
+	}
+
+	err = writeHeaderCards(f, hdr.slice)
+	if err != nil {
+		return hdu, err
+	}
+
+	return f.appendImageHDUs()
+}
+
+// CompressImage compresses the image of hdu into a new image extension of File out,
+// with compression parameters c.
+func CompressImage(out *File, hdu *ImageHDU, c Compression) (*ImageHDU, error) {
+	err := hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	// restore the defaults even if set failed half-way.
+	defer c.reset(out)
+	err = c.set(out)
+	if err != nil {
+		return nil, err
+	}
+
+	c_status := C.int(0)
+	C.fits_img_compress(hdu.f.c, out.c, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+	return out.appendImageHDUs()
+}
+
+// DecompressImage uncompresses the tile-compressed image of hdu into a new image HDU of File out.
+// If out is empty, the image is written into its Primary HDU.
+func DecompressImage(out *File, hdu *ImageHDU) (*ImageHDU, error) {
+	err := hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	c_status := C.int(0)
+	C.fits_img_decompress(hdu.f.c, out.c, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+	return out.appendImageHDUs()
+}
+
+// IsCompressed returns whether the image of this HDU is tile-compressed.
+func (hdu *ImageHDU) IsCompressed() (bool, error) {
+	err := hdu.seekHDU()
+	if err != nil {
+		return false, err
+	}
+	c_status := C.int(0)
+	o := C.fits_is_compressed_image(hdu.f.c, &c_status)
+	if c_status > 0 {
+		return false, to_err(c_status)
+	}
+	return o != 0, nil
+}
+
+// appendImageHDUs registers the HDUs CFITSIO appended to f since the last
+// update of f.hdus, and returns the last one.
+func (f *File) appendImageHDUs() (*ImageHDU, error) {
+	nhdus, err := f.NumHDUs()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(f.hdus); i < nhdus; i++ {
+		hdu, err := f.readHDU(i)
+		if err != nil {
+			return nil, err
+		}
+		f.hdus = append(f.hdus, hdu)
+	}
+
+	switch hdu := f.hdus[len(f.hdus)-1].(type) {
+	case *ImageHDU:
+		return hdu, nil
+	case *PrimaryHDU:
+		return &hdu.ImageHDU, nil
+	default:
+		return nil, fmt.Errorf("cfitsio: last HDU is not an image (%T)", hdu)
+	}
+}
+
+// EOF
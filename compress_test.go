@@ -0,0 +1,205 @@
+package cfitsio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestCompressedImage(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	axes := []int64{8, 4}
+	image := make([]int32, 32)
+	for i := range image {
+		image[i] = int32(i % 5)
+	}
+	fimage := make([]float64, 32)
+	for i := range fimage {
+		fimage[i] = float64(i) * 0.25
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			hdu, err := NewCompressedImageHDU(
+				&f,
+				NewHeader([]Card{{"EXTNAME", "RICE", ""}}, IMAGE_HDU, 32, axes),
+				Compression{Type: RiceCompression, Tile: []int64{4, 2}},
+			)
+			if err != nil {
+				t.Fatalf("error creating compressed image: %v", err)
+			}
+			if len(f.HDUs()) != 2 {
+				t.Fatalf("expected 2 HDUs. got %d", len(f.HDUs()))
+			}
+			err = hdu.Write(&image)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			hdu, err = NewCompressedImageHDU(
+				&f,
+				NewHeader([]Card{{"EXTNAME", "GZIP", ""}}, IMAGE_HDU, -64, axes),
+				Compression{Type: GzipCompression, Lossless: true},
+			)
+			if err != nil {
+				t.Fatalf("error creating compressed image: %v", err)
+			}
+			err = hdu.Write(&fimage)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			_, err = NewCompressedImageHDU(&f, NewHeader(nil, IMAGE_HDU, 32, axes), Compression{})
+			if err == nil {
+				t.Fatalf("expected an error creating a compressed image without compression type")
+			}
+
+			// images created afterwards are not compressed.
+			hdu, err = NewImageHDU(&f, NewHeader(nil, IMAGE_HDU, 32, axes))
+			if err != nil {
+				t.Fatalf("error creating image: %v", err)
+			}
+			err = hdu.Write(&image)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+			compressed, err := hdu.IsCompressed()
+			if err != nil {
+				t.Fatalf("error checking compression: %v", err)
+			}
+			if compressed {
+				t.Fatalf("expected an uncompressed image")
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			hdu := f.HDU(1).(*ImageHDU)
+			compressed, err := hdu.IsCompressed()
+			if err != nil {
+				t.Fatalf("error checking compression: %v", err)
+			}
+			if !compressed {
+				t.Fatalf("expected a compressed image")
+			}
+			hdr := hdu.Header()
+			if !reflect.DeepEqual(hdr.Axes(), axes) {
+				t.Fatalf("expected axes %v. got %v", axes, hdr.Axes())
+			}
+			data := make([]int32, len(image))
+			err = hdu.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !reflect.DeepEqual(data, image) {
+				t.Fatalf("expected image %v. got %v", image, data)
+			}
+
+			// the GZIP image does not inherit the tiles of the RICE image.
+			for i, tile := range [][]int64{{4, 2}, {8, 1}} {
+				hdr := f.HDU(i + 1).Header()
+				for j, want := range tile {
+					n := fmt.Sprintf("ZTILE%d", j+1)
+					card := hdr.Get(n)
+					if card == nil {
+						t.Fatalf("hdu #%d: error retrieving card [%s]", i+1, n)
+					}
+					if card.Value != want {
+						t.Fatalf("hdu #%d: card %s. expected [%v]. got [%v]", i+1, n, want, card.Value)
+					}
+				}
+			}
+
+			fdata := make([]float64, len(fimage))
+			err = f.HDU(2).Data(&fdata)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !reflect.DeepEqual(fdata, fimage) {
+				t.Fatalf("expected image %v. got %v", fimage, fdata)
+			}
+
+			// decompress, then compress again.
+			out, err := CreateInMemory()
+			if err != nil {
+				t.Fatalf("error creating in-memory file: %v", err)
+			}
+			defer out.Close()
+
+			dhdu, err := DecompressImage(&out, hdu)
+			if err != nil {
+				t.Fatalf("error decompressing image: %v", err)
+			}
+			compressed, err = dhdu.IsCompressed()
+			if err != nil {
+				t.Fatalf("error checking compression: %v", err)
+			}
+			if compressed {
+				t.Fatalf("expected an uncompressed image")
+			}
+			data = make([]int32, len(image))
+			err = dhdu.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !reflect.DeepEqual(data, image) {
+				t.Fatalf("expected image %v. got %v", image, data)
+			}
+
+			chdu, err := CompressImage(&out, dhdu, Compression{Type: PlioCompression})
+			if err != nil {
+				t.Fatalf("error compressing image: %v", err)
+			}
+			compressed, err = chdu.IsCompressed()
+			if err != nil {
+				t.Fatalf("error checking compression: %v", err)
+			}
+			if !compressed {
+				t.Fatalf("expected a compressed image")
+			}
+			data = make([]int32, len(image))
+			err = chdu.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !reflect.DeepEqual(data, image) {
+				t.Fatalf("expected image %v. got %v", image, data)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	fits "github.com/astrogo/cfitsio"
+)
+
+func main() {
+	flag.Usage = func() {
+		const msg = `Usage: go-cfitsio-verify filename [filename...]
+
+Verify the conformance of FITS files to the FITS standard:
+order and values of the mandatory keywords, types of the reserved
+keywords, consistency of the table column keywords, header and data
+padding, BLANK/BITPIX compatibility and CHECKSUM/DATASUM validity.
+
+The exit status is 1 if an error was found in any of the files.
+
+Example:
+  go-cfitsio-verify file.fits
+`
+		fmt.Fprintf(os.Stderr, "%v\n", msg)
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	rc := 0
+	for _, fname := range flag.Args() {
+		if !verify(fname) {
+			rc = 1
+		}
+	}
+	os.Exit(rc)
+}
+
+// verify prints the verification report of the file fname and returns whether it is valid.
+func verify(fname string) bool {
+	fmt.Printf("File: %s\n", fname)
+
+	var report fits.Report
+	f, err := fits.Open(fname, fits.ReadOnly)
+	if err != nil {
+		// files CFITSIO can not open are reported like any other problem.
+		report.Issues = append(report.Issues, fits.Issue{
+			Severity: fits.SeverityError,
+			HDU:      -1,
+			Msg:      fmt.Sprintf("could not open file: %v", err),
+		})
+	} else {
+		defer f.Close()
+		report = fits.Verify(&f)
+	}
+	for i := 0; i < report.NumHDUs; i++ {
+		hdu := f.HDU(i)
+		fmt.Printf("\nHDU #%d: %s (%v)\n", i, hdu.Name(), hdu.Type())
+		issues := report.HDU(i)
+		for _, issue := range issues {
+			if issue.Card == "" {
+				fmt.Printf("  *** %v: %s\n", issue.Severity, issue.Msg)
+				continue
+			}
+			fmt.Printf("  *** %v: %s: %s\n", issue.Severity, issue.Card, issue.Msg)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("  no problem found\n")
+		}
+	}
+	for _, issue := range report.HDU(-1) {
+		fmt.Printf("*** %v: %s\n", issue.Severity, issue.Msg)
+	}
+
+	fmt.Printf("\n%d error(s), %d warning(s)\n\n", report.NumErrors(), report.NumWarnings())
+	return report.OK()
+}
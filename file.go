@@ -4,14 +4,31 @@ package cfitsio
 // #include <stdlib.h>
 // #include "go-cfitsio.h"
 // #include "go-cfitsio-utils.h"
+//
+// typedef struct {
+//   void  *ptr;
+//   size_t len;
+// } memfile_t;
+//
+// static int memfile_open(fitsfile **fptr, const char *name, int mode, memfile_t *mem, size_t delta, int *status) {
+//   return fits_open_memfile(fptr, name, mode, &mem->ptr, &mem->len, delta, realloc, status);
+// }
+//
+// static int memfile_create(fitsfile **fptr, memfile_t *mem, size_t delta, int *status) {
+//   return fits_create_memfile(fptr, &mem->ptr, &mem->len, delta, realloc, status);
+// }
 import "C"
 
 import (
+	"fmt"
 	"unsafe"
 )
 
 type Mode int
 
+// maxInt is the largest value of an int, the size limit of a []byte.
+const maxInt = int64(^uint(0) >> 1)
+
 const (
 	ReadOnly  Mode = C.READONLY
 	ReadWrite Mode = C.READWRITE
@@ -19,10 +36,19 @@ const (
 
 // File is a handle to a FITS file
 type File struct {
-	c    *C.fitsfile
-	hdus []HDU
+	c      *C.fitsfile
+	hdus   []HDU
+	mem    *C.memfile_t // memory buffer backing an in-memory file, nil otherwise
+	stream *stream      // Go stream backing a streamed file, nil otherwise
+	chksum bool         // whether to update checksums on Close
 }
 
+// memFileName is the name given to FITS files living in memory
+const memFileName = "mem.fits"
+
+// memFileDelta is the increment by which the memory buffer of an in-memory file grows
+const memFileDelta = 2880
+
 // HDUs returns the list of all Header-Data Unit blocks in the file
 func (f *File) HDUs() []HDU {
 	return f.hdus
@@ -43,6 +69,41 @@ func Open(fname string, mode Mode) (File, error) {
 		return f, to_err(c_status)
 	}
 
+	err = f.readHDUs()
+	return f, err
+}
+
+// OpenBytes opens an in-memory FITS file whose content is buf.
+// buf is copied into a buffer managed by CFITSIO and may be reused by the caller.
+// OpenBytes will create HDU values, loading the Header part but leaving the Data part in memory.
+func OpenBytes(buf []byte, mode Mode) (File, error) {
+	var f File
+	var err error
+
+	if len(buf) == 0 {
+		return f, fmt.Errorf("cfitsio: empty FITS buffer")
+	}
+
+	f.mem = newMemFile(len(buf))
+	C.memcpy(f.mem.ptr, unsafe.Pointer(&buf[0]), C.size_t(len(buf)))
+
+	c_status := C.int(0)
+	c_fname := C.CString(memFileName)
+	defer C.free(unsafe.Pointer(c_fname))
+
+	C.memfile_open(&f.c, c_fname, C.int(mode), f.mem, memFileDelta, &c_status)
+	if c_status > 0 {
+		freeMemFile(f.mem)
+		f.mem = nil
+		return f, to_err(c_status)
+	}
+
+	err = f.readHDUs()
+	return f, err
+}
+
+// readHDUs creates the HDU values of a freshly opened file.
+func (f *File) readHDUs() error {
 	// ffopen might have moved to specific HDU (via fname specifications)
 	// remember it and go back to that one after we've dealt with "our" HDUs
 	ihdu := f.HDUNum()
@@ -50,22 +111,22 @@ func Open(fname string, mode Mode) (File, error) {
 
 	nhdus, err := f.NumHDUs()
 	if err != nil {
-		return f, err
+		return err
 	}
 
 	f.hdus = make([]HDU, 0, nhdus)
 	for i := 0; i < nhdus; i++ {
 		hdu, err := f.readHDU(i)
 		if err != nil {
-			return f, err
+			return err
+		}
+		if p, ok := hdu.(pristineHDU); ok {
+			p.setPristine(true)
 		}
 		f.hdus = append(f.hdus, hdu)
 	}
-	if err != nil {
-		return f, err
-	}
 
-	return f, err
+	return err
 }
 
 // Create creates and opens a new empty output FITS file.
@@ -88,18 +149,115 @@ func Create(fname string) (File, error) {
 	return f, err
 }
 
+// CreateInMemory creates and opens a new empty FITS file living in memory.
+// Use Bytes to retrieve the content of the file.
+func CreateInMemory() (File, error) {
+	var f File
+	var err error
+
+	f.mem = newMemFile(memFileDelta)
+
+	c_status := C.int(0)
+	C.memfile_create(&f.c, f.mem, memFileDelta, &c_status)
+	if c_status > 0 {
+		freeMemFile(f.mem)
+		f.mem = nil
+		err = to_err(c_status)
+		return f, err
+	}
+
+	f.hdus = make([]HDU, 0)
+
+	return f, err
+}
+
+// newMemFile allocates a memory buffer of n bytes for an in-memory file.
+func newMemFile(n int) *C.memfile_t {
+	mem := (*C.memfile_t)(C.malloc(C.size_t(unsafe.Sizeof(C.memfile_t{}))))
+	mem.ptr = C.malloc(C.size_t(n))
+	mem.len = C.size_t(n)
+	return mem
+}
+
+// freeMemFile releases the memory buffer of an in-memory file.
+func freeMemFile(mem *C.memfile_t) {
+	C.free(mem.ptr)
+	C.free(unsafe.Pointer(mem))
+}
+
+// Bytes returns the content of a FITS file living in memory (see OpenBytes and CreateInMemory).
+// Pending changes are flushed first. Bytes must be called before Close.
+func (f *File) Bytes() ([]byte, error) {
+	if f.mem == nil {
+		return nil, fmt.Errorf("cfitsio: file is not an in-memory file")
+	}
+
+	c_status := C.int(0)
+	C.fits_flush_file(f.c, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	// the buffer grows by chunks: the FITS content ends with the last HDU.
+	if uint64(f.mem.len) > uint64(maxInt) {
+		return nil, fmt.Errorf("cfitsio: in-memory file too large (%d bytes)", uint64(f.mem.len))
+	}
+	size := int64(f.mem.len)
+	nhdus, err := f.NumHDUs()
+	if err != nil {
+		return nil, err
+	}
+	if nhdus > 0 {
+		ihdu := f.HDUNum()
+		defer f.SeekHDU(ihdu, 0)
+
+		err = f.SeekHDU(nhdus-1, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		c_head := C.LONGLONG(0)
+		c_data := C.LONGLONG(0)
+		c_end := C.LONGLONG(0)
+		C.fits_get_hduaddrll(f.c, &c_head, &c_data, &c_end, &c_status)
+		if c_status > 0 {
+			return nil, to_err(c_status)
+		}
+		if int64(c_end) < size {
+			size = int64(c_end)
+		}
+	}
+
+	buf := make([]byte, size)
+	if size > 0 {
+		C.memcpy(unsafe.Pointer(&buf[0]), f.mem.ptr, C.size_t(size))
+	}
+	return buf, nil
+}
+
 // Close closes a previously opened FITS file.
+// If SetChecksumOnClose was enabled, the checksums of the modified HDUs are updated first.
 func (f *File) Close() error {
+	var err error
+	if f.chksum {
+		err = f.updateChecksums()
+	}
 	c_status := C.int(0)
 	C.fits_close_file(f.c, &c_status)
-	err := to_err(c_status)
-	if err != nil {
-		return err
+	if f.mem != nil {
+		freeMemFile(f.mem)
+		f.mem = nil
+	}
+	if err == nil && f.stream != nil {
+		err = f.stream.err
+	}
+	if err == nil {
+		err = to_err(c_status)
 	}
 
 	for _, hdu := range f.hdus {
 		err2 := hdu.Close()
-		if err2 != nil {
+		if err == nil {
 			err = err2
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -428,4 +429,241 @@ func TestCreateFile(t *testing.T) {
 
 }
 
+func TestOpenBytes(t *testing.T) {
+	const fname = "testdata/file001.fits"
+	buf, err := ioutil.ReadFile(fname)
+	if err != nil {
+		t.Fatalf("error reading file [%s]: %v", fname, err)
+	}
+
+	f, err := OpenBytes(buf, ReadOnly)
+	if err != nil {
+		t.Fatalf("could not open FITS buffer: %v", err)
+	}
+	defer f.Close()
+
+	fmode, err := f.Mode()
+	if err != nil {
+		t.Fatalf("error mode: %v", err)
+	}
+	if fmode != ReadOnly {
+		t.Fatalf("expected file-mode [%v]. got [%v]", ReadOnly, fmode)
+	}
+
+	nhdus, err := f.NumHDUs()
+	if err != nil {
+		t.Fatalf("error hdu: %v", err)
+	}
+	if nhdus != 2 {
+		t.Fatalf("expected #hdus [%v]. got [%v]", 2, nhdus)
+	}
+	if len(f.HDUs()) != nhdus {
+		t.Fatalf("#hdus. expected %v. got %v", nhdus, len(f.HDUs()))
+	}
+
+	if f.HDU(1).Type() != ASCII_TBL {
+		t.Fatalf("expected hdu type [%v]. got [%v]", ASCII_TBL, f.HDU(1).Type())
+	}
+
+	out, err := f.Bytes()
+	if err != nil {
+		t.Fatalf("error bytes: %v", err)
+	}
+	if !bytes.Equal(out, buf) {
+		t.Fatalf("in-memory file content differs from original file")
+	}
+}
+
+func TestCreateInMemory(t *testing.T) {
+	var buf []byte
+	image := []int16{
+		0, 1, 2, 3,
+		4, 5, 6, 7,
+		8, 9, 0, 1,
+	}
+
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := CreateInMemory()
+			if err != nil {
+				t.Fatalf("error creating in-memory file: %v", err)
+			}
+			defer f.Close()
+
+			if len(f.HDUs()) != 0 {
+				t.Fatalf("#hdus. expected %v. got %v", 0, len(f.HDUs()))
+			}
+
+			hdr := NewHeader(nil, IMAGE_HDU, 16, []int64{4, 3})
+			phdu, err := NewPrimaryHDU(&f, hdr)
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			img := phdu.(*PrimaryHDU)
+			err = img.Write(&image)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			buf, err = f.Bytes()
+			if err != nil {
+				t.Fatalf("error bytes: %v", err)
+			}
+			if len(buf) == 0 || len(buf)%2880 != 0 {
+				t.Fatalf("invalid FITS buffer size (%d)", len(buf))
+			}
+		},
+		// read-back
+		func() {
+			f, err := OpenBytes(buf, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening FITS buffer: %v", err)
+			}
+			defer f.Close()
+
+			hdu := f.HDU(0)
+			hdr := hdu.Header()
+			if hdr.Bitpix() != 16 {
+				t.Fatalf("expected BITPIX=%v. got %v", 16, hdr.Bitpix())
+			}
+
+			data := make([]int16, len(image))
+			err = hdu.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading data: %v", err)
+			}
+			if !reflect.DeepEqual(data, image) {
+				t.Fatalf("expected image:\n%v\ngot:\n%v", image, data)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestOpenReader(t *testing.T) {
+	const fname = "testdata/swp06542llg.fits"
+	r, err := os.Open(fname)
+	if err != nil {
+		t.Fatalf("error opening file [%s]: %v", fname, err)
+	}
+	defer r.Close()
+
+	f, err := OpenReader(r)
+	if err != nil {
+		t.Fatalf("could not open FITS stream: %v", err)
+	}
+	defer f.Close()
+
+	ref, err := Open(fname, ReadOnly)
+	if err != nil {
+		t.Fatalf("could not open FITS file [%s]: %v", fname, err)
+	}
+	defer ref.Close()
+
+	if len(f.HDUs()) != len(ref.HDUs()) {
+		t.Fatalf("#hdus. expected %v. got %v", len(ref.HDUs()), len(f.HDUs()))
+	}
+	for i := range ref.HDUs() {
+		hdr := f.HDU(i).Header()
+		ref := ref.HDU(i).Header()
+		if !reflect.DeepEqual(hdr.Keys(), ref.Keys()) {
+			t.Fatalf("hdu #%d: expected keys:\n%v\ngot:\n%v", i, ref.Keys(), hdr.Keys())
+		}
+	}
+
+	fmode, err := f.Mode()
+	if err != nil {
+		t.Fatalf("error mode: %v", err)
+	}
+	if fmode != ReadOnly {
+		t.Fatalf("expected file-mode [%v]. got [%v]", ReadOnly, fmode)
+	}
+}
+
+func TestOpenReaderInvalid(t *testing.T) {
+	g_streams.Lock()
+	n := len(g_streams.m)
+	g_streams.Unlock()
+
+	_, err := OpenReader(strings.NewReader("not a FITS file"))
+	if err == nil {
+		t.Fatalf("expected an error opening an invalid FITS stream")
+	}
+
+	g_streams.Lock()
+	defer g_streams.Unlock()
+	if len(g_streams.m) != n {
+		t.Fatalf("expected %d registered streams. got %d", n, len(g_streams.m))
+	}
+}
+
+func TestCreateWriter(t *testing.T) {
+	image := []float64{
+		0, 1, 2, 3,
+		4, 5, 6, 7,
+		8, 9, 0, 1,
+	}
+
+	buf := new(bytes.Buffer)
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := CreateWriter(buf)
+			if err != nil {
+				t.Fatalf("error creating FITS stream: %v", err)
+			}
+
+			hdr := NewHeader(nil, IMAGE_HDU, -64, []int64{4, 3})
+			phdu, err := NewPrimaryHDU(&f, hdr)
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			img := phdu.(*PrimaryHDU)
+			err = img.Write(&image)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			if buf.Len() != 0 {
+				t.Fatalf("FITS stream written before Close")
+			}
+
+			err = f.Close()
+			if err != nil {
+				t.Fatalf("error closing FITS stream: %v", err)
+			}
+
+			if buf.Len() == 0 || buf.Len()%2880 != 0 {
+				t.Fatalf("invalid FITS stream size (%d)", buf.Len())
+			}
+		},
+		// read-back
+		func() {
+			f, err := OpenReader(buf)
+			if err != nil {
+				t.Fatalf("error opening FITS stream: %v", err)
+			}
+			defer f.Close()
+
+			hdu := f.HDU(0)
+			data := make([]float64, len(image))
+			err = hdu.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading data: %v", err)
+			}
+			if !reflect.DeepEqual(data, image) {
+				t.Fatalf("expected image:\n%v\ngot:\n%v", image, data)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
 // EOF
@@ -0,0 +1,75 @@
+#include "go-cfitsio.h"
+#include "_cgo_export.h"
+
+/* fits_register_driver is declared in fitsio2.h, which is not always installed. */
+int fits_register_driver(char *prefix,
+	int (*init)(void),
+	int (*fitsshutdown)(void),
+	int (*setoptions)(int option),
+	int (*getoptions)(int *options),
+	int (*getversion)(int *version),
+	int (*checkfile)(char *urltype, char *infile, char *outfile),
+	int (*fitsopen)(char *filename, int rwmode, int *driverhandle),
+	int (*fitscreate)(char *filename, int *driverhandle),
+	int (*fitstruncate)(int driverhandle, LONGLONG filesize),
+	int (*fitsclose)(int driverhandle),
+	int (*fremove)(char *filename),
+	int (*size)(int driverhandle, LONGLONG *size),
+	int (*flush)(int driverhandle),
+	int (*seek)(int driverhandle, LONGLONG offset),
+	int (*fitsread)(int driverhandle, void *buffer, long nbytes),
+	int (*fitswrite)(int driverhandle, void *buffer, long nbytes));
+
+static int
+stream_init(void)
+{
+	return 0;
+}
+
+static int
+stream_shutdown(void)
+{
+	return 0;
+}
+
+static int
+stream_setoptions(int options)
+{
+	return 0;
+}
+
+static int
+stream_getoptions(int *options)
+{
+	*options = 0;
+	return 0;
+}
+
+static int
+stream_getversion(int *version)
+{
+	*version = 10;
+	return 0;
+}
+
+int
+go_cfitsio_register_stream_driver(void)
+{
+	return fits_register_driver("gostream://",
+		stream_init,
+		stream_shutdown,
+		stream_setoptions,
+		stream_getoptions,
+		stream_getversion,
+		go_cfitsio_stream_checkfile,
+		go_cfitsio_stream_open,
+		go_cfitsio_stream_create,
+		go_cfitsio_stream_truncate,
+		go_cfitsio_stream_close,
+		go_cfitsio_stream_remove,
+		go_cfitsio_stream_size,
+		go_cfitsio_stream_flush,
+		go_cfitsio_stream_seek,
+		go_cfitsio_stream_read,
+		go_cfitsio_stream_write);
+}
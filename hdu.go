@@ -156,6 +156,83 @@ func (f *File) HDUType() (HDUType, error) {
 	return HDUType(c_hdu), nil
 }
 
+// DeleteHDU deletes the i-th HDU (index: 0-based!) from the file.
+// The following HDUs are renumbered. The Primary HDU can not be deleted.
+func (f *File) DeleteHDU(i int) error {
+	if i == 0 {
+		return fmt.Errorf("cfitsio: the Primary HDU can not be deleted")
+	}
+	if i < 0 || i >= len(f.hdus) {
+		return fmt.Errorf("cfitsio: invalid HDU index %d", i)
+	}
+
+	_, err := f.seekHDU(i, 0)
+	if err != nil {
+		return err
+	}
+
+	c_status := C.int(0)
+	C.fits_delete_hdu(f.c, nil, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	f.hdus[i].Close()
+	f.hdus = append(f.hdus[:i], f.hdus[i+1:]...)
+	f.renumberHDUs(i)
+	return nil
+}
+
+// insertHDU seeks to the HDU preceding the (future) i-th HDU (index: 0-based!), so a new HDU
+// can be inserted at that position.
+// i == len(f.hdus) inserts after the last HDU.
+func (f *File) insertHDU(i int) error {
+	mode, err := f.Mode()
+	if err != nil {
+		return err
+	}
+	if mode == ReadOnly {
+		return READONLY_FILE
+	}
+	if i == 0 {
+		return fmt.Errorf("cfitsio: an HDU can not be inserted before the Primary HDU")
+	}
+	if i < 0 || i > len(f.hdus) {
+		return fmt.Errorf("cfitsio: invalid HDU index %d", i)
+	}
+	_, err = f.seekHDU(i-1, 0)
+	return err
+}
+
+// registerHDU reads the newly inserted i-th HDU (index: 0-based!) and adds it to the cache,
+// renumbering the following HDUs.
+func (f *File) registerHDU(i int) (HDU, error) {
+	hdu, err := f.readHDU(i)
+	if err != nil {
+		return nil, err
+	}
+	f.hdus = append(f.hdus, nil)
+	copy(f.hdus[i+1:], f.hdus[i:])
+	f.hdus[i] = hdu
+	f.renumberHDUs(i + 1)
+	return hdu, nil
+}
+
+// renumberHDUs updates the HDU numbers of the cached HDUs, starting at the i-th one.
+func (f *File) renumberHDUs(i int) {
+	for j := i; j < len(f.hdus); j++ {
+		id := C.int(j + 1) // 0-based to 1-based index
+		switch hdu := f.hdus[j].(type) {
+		case *PrimaryHDU:
+			hdu.id = id
+		case *ImageHDU:
+			hdu.id = id
+		case *Table:
+			hdu.id = id
+		}
+	}
+}
+
 // Copy all or part of the HDUs in the FITS file associated with infptr and append them to the end of the FITS file associated with outfptr. If 'previous' is true, then any HDUs preceding the current HDU in the input file will be copied to the output file. Similarly, 'current' and 'following' determine whether the current HDU, and/or any following HDUs in the input file will be copied to the output file. Thus, if all 3 parameters are true, then the entire input file will be copied. On exit, the current HDU in the input file will be unchanged, and the last HDU in the output file will be the current HDU.
 func (f *File) Copy(out *File, previous, current, following bool) error {
 	c_previous := C.int(0)
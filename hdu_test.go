@@ -1,6 +1,8 @@
 package cfitsio
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 )
@@ -115,6 +117,896 @@ var g_tables = []struct {
 							Value:   0.0,
 							Comment: "THDA at end of exposure",
 						},
+						{
+							Name:    "COMMENT",
+							Value:   "*",
+							Comment: "",
+						},
+						{
+							Name:    "COMMENT",
+							Value:   "* THE IUE VICAR HEADER",
+							Comment: "",
+						},
+						{
+							Name:    "COMMENT",
+							Value:   "*",
+							Comment: "",
+						},
+						{
+							Name:    "COMMENT",
+							Value:   "IUE-VICAR HEADER START",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                        0001000100071204   1 2 013106542            1  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "  1445*   4*IUESOC  *   *   *  3600*      *   *  * * * * * *     *  2  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "SWP6542, NGC 7027, 60 MIN, LG APER, LO DISP                         3  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                    4  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                    5  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "PROGRAM:NPBRB   OBSERVER:BOHLIN   DATE:1979.2609.260  17SEP         6  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                    7  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                    8  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                    9  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "79260123556* 9   * 218 *OPSDEV14*112438 RDXSPREP 2 IMAGE 5614    * 10  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "091608 SCAN READLO SS 1 G3 58   *112511 SCAN READLO SS 1 G3 58   * 11  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "091623 X 56 Y 72 G1 99 HT 106   *112525 X 56 Y 72 G1 99 HT 106   * 12  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "093518 TLM,FES2ROM              *114939 TLM,FES2ROM              * 13  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101100 FIN 3 T 3599 S 97 U 109  *120916 FESTRK TRACKING          * 14  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101150 MODE LWL                 *121950 FIN 3 T 3599 S 97 U 109  * 15  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101239 TARGET FROM SWLA         *122052 TARGET FROM SWLA         * 16  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101436 TARGET IN LWLA           *122532 TARGET IN LWLA           * 17  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101542 EXPOBC 2 59 59  MAXG NOL *122642 EXPOBC 2 59 59  MAXG NOL * 18  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101755 FESTRK TRACKING          *122948 FESTRK TRACKING          * 19  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101919 TLM,SWPROM               *123115 TLM,SWPROM               * 20  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "101957 READPREP 3 IMAGE 6541    *123556 RDXSPREP 3 IMAGE 6542    * 21  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "102029 SCAN READLO SS 1 G3 44   *123631 SCAN READLO SS 1 G3 44   * 22  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "102045 X 60 Y 76 G1 82 HT 105   *123648 X 60 Y 76 G1 82 HT 105   * 23  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "104652 TLM,FES2ROM              *123621                          * 24  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "111319 MODE SWL                 *123646                          * 25  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "111545 FIN 2 T 3599 S 98 U 109  *090650 ACQ STARTED              * 26  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "111646 TARGET FROM LWLA         *090952 TARGET IN SWLA           * 27  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "111841 TARGET IN SWLA           *091005 FES 761  IN 20 0 0       * 28  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "111953 EXPOBC 3 59 59  MAXG NOL *091059 EXPOBC 3 59 59  MAXG NOL * 29  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "112211 FESTRK TRACKING          *091257 FESTRK TRACKING          * 30  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "112328 TLM,LWRROM               *091415 TLM,LWRROM  .200000E 02  * 31  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "112412 MODE LWH                 *091534 READPREP 2 IMAGE 5613    * 32  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   33  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   34  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   35  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "NPBRB*1*02*BOHLIN          *  7*   *N*00007027*0*0*1* 70           36  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "21 5 94+42 2 3*999* 0*0*99.0*99.00* 0.0     *   0* 120.00*  0*     37  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                 ' i  cf@fh=   %cc                 38  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   39  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "   ?4 3    t ] D \"        4 3c3D3 4                                40  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   41  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   42  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "8        r 4     M   , i ) K n c D   H                             43  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   44  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   45  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   46  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   47  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   48  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   49  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   50  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 020 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 51",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 0 0 04040 51  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 52",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 8 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 020 0 04040 52  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 020 0 0 0 0 0 0 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 53",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 04040 53  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 2 080 0 0 0 0 0 0 0 0 0 54",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 0 0 0 0 0 0 0 0 04040 54  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 2 8 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 55",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 04040 55  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 8 020 0 0 0 0 0 0 0 0 0 0 0 56",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 020 0 0 0 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 0 0 0 0 0 0 04040 56  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 8 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 020 0 0 0 0 0a0 0 0 0 8 0 0 0 57",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 04040 57  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 8 0 0 0 0 0 0 0 0 0 0 0 0 0 0 58",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 020 0 0 0 0 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 04040 58  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "20 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 080 0 2 0 0 0 0 080 0 59",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 2 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 2 080 0 0 0 0 0 0 0 0 0 0 0 04040 59  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 2 0 0 0 0 0 0 0 020 0 0 0 0 0 0 0 0 0 60",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 080 0 820 0 030 0 0 0 0 0 0 0 0 8 0 0 0 08030 04040 60  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 8 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 0 0 0 0 8 0 0 0 0 61",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 020 0 0 0 0 0 0 020 0 0 0 0 0 0 0 0 020 080 0 0 0 04040 61  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 0 0 0 2 0 0 8 030 0 0 0 0 62",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 2 0 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 0 0 0 0 0 0 0 0 8 0 0 04040 62  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 222 0 0 2 0 0 0 2 280 0 0 0 0 0 0 8 0 0 0 0 2 0 0 0 0 0 0 0 0 020 63",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 028 0 0 0 0 0 0 0 0 0 0 0 020 0 0 080 020 0 0 0 0 0204040 63  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 028 8 0 0 0 0 0 0 0 0 0 0 0 0 0 8 2 020a0 0 0 0 0 0 2 0 64",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 2 0 0 0 0 0 0 0 0 8 0a8 0 0 0 0 0 0 020 0 0 02020 0 0 0 0 0 04040 64  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 c 0 0 0 0 0 0 0 0 0 0 0 8 0 0 0 0 028 0 8 0 0 2 0 0282020 0 65",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 2 0 8 0 020 0 0 0 0 0 0 0 0 0 2 0c0 03020 020 080 0 0 0 2 04040 65  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "80 0 2 0 0 0 0 0 08020 0 a 0 0 0 2 0 0 080 0 020 8 0 0 0 0 0 0 0 0 66",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 080 0 0 0 080 0 0 0 c 0 0 080 0 0 8 0 0 8 0 8 0 0 8 0 020 04040 66  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "80 0 8 0 018 0 020 0 0 0 0 0 0 0 0 080 0 8 0 020 0 8 0 0 0a0 2 0 8 67",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 080 0 0 0 8 0 2 0 030 0 0 0 0 0 0 0 020 020 8 8 0 8 0 04040 67  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 8 02080 8 0 0 0 0 0 0 0 0bffff0 0 0 0 0 2 0 2 0 0 0 0 0 0 2 68",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 3 0 0 0 082 2 c 0 0 02080 0 0 0 0 0 0 0 2 0 8a2 0 0 04040 68  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 c80 8 0 0 080 2 0 0 022 8 880 0 0 0 0 0 0 0 0 8 0 0 2 0 0 0 0 0 69",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 280 0 8 0 0 0c8 08020 0 0 0 0 0 0 0 0 088 0 0 0 0 8 0 04040 69  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 080 2 0 0 0 0 08280 8 0 2 8 0 0 2 2 3 020 020 0 0 0 0a0 2 a 0 0 70",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " a 0 0 0 2 8 08080 020a0 0 2202ffff0 0 0 0 0 0 0 0 0 0 0 080 04040 70  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 08222 c 0 2 0 0 0 0 282 0 080 020 0 0 0 a 280 0 0 2 0 080 0 820a2 71",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "22 020 020 0 8 020 8 020 0 0 2 0 8 0 080 2 280 08022 2 0 0 0 24040 71  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "2220 8 0 8 0 0 0 0 0 2 02080 8 2 0 0 0 2 0 020 082 0 0 0 0 02020 0 72",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 02088 0 0 082 82020 0 280 8 8 8 8 820 0 a8020 020 8 0 2 2 0 24040 72  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 2 0 8 0a2 0 088 0 0 0 2 2808020 0 220 0 2 0 0 0 8 0 a 0b0 0 0 b 73",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 280 0 0 0 0 0 0 0 2 0 020 080 0 0a080 8 0a0 0 8 0 2 0 fc0 0824040 73  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 880 02088 0 2 0 0 0 2 0 0aa82 0 280 0 0 880 08022 0 0 a 288 0 8 74",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "88 c 08a28 0 0 a 080 0 820 0 2808220 8a0 0 0 0208220 0 0 0 0 34040 74  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 75",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 04040 75  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " c1af6fbcc 0 0 09d707b 0c6 e 03dc8 c32474e366a29253f47413320314d64 76",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "29 03d8af6 51a1c9090 05e7d8233122e2a19282e1d32 f18117da5abab 0 0 0 76  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "ac30 0ff 0 439abbba0 0445aa4 4371f2a31342b1d1d2c2f151914161d502d37 77",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "70727e207b197a227b14259a1e92209420951e96 1 0 1b8e1 0 0e02ce0fa3540 77  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "7b 07b 072 07b7b7b7b7b 039 0 07f4c4541ae547c979951ceae9580808645 0 78",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 03a3234363a302f2f3335332b35bdad 6c1f6568282 0 1 08282404040404040 78  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 6c2f2568381 0 1 080803b3535343b35313036373633338977877945776b 79",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "796a7b2f7f7e7f7a7a34777d7f7e8e487f40404040404040404040404040404040 79  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "993837611019 e89 0cc 040404040404040404040404040404040404040404040 80",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "404040404040404040404040404040404040404040404040404040404040404040 80  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " dfbffbe 0 0 0 07f374399 2 2fe66fb30 0 0 0 0 0 0d0 0 0 0 0 0 0 080 81",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0 0 0 0 0 0 0 0 088 0 0 0 c 0 0 0 c 0 0 0 0 0 0 0ff 0 0 04040 81  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "4f1960 0 0 0 0 0ff 0 0 0ff 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 82",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 0 0ff 0 0 0ff 0 0 0 0 0 0 0ff 0 0 0ff 0 0 0 0 0 0 0 0 0 0 04040 82  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   83  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   84  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "                                                                   85  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " b2b 0 0 017 0 0 0 689a1e02ce0e1 0 021 0 016809e32 0 0dcc1f5a15e3b 86",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "b1dad9565668c969c92fc9 0 061415142 0 0404040 2 3 c d e404040404040 86  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " c12 0 0 0 5 0 0 0 38a9ae02ce0e1 0 026 0 016809e32baab 6c1f2568381 87",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 07f807e7f7a7a3477 0 061414140 0 0404040 3 635 b c404040404040 87  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " c1a 0 0 0 5 0 0 0 38999e02ce0e1 0 02b 0 016809e32bdad 6c1f6568282 88",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 082826b776a7b2f7e 0 061404141 0 0404040 2 432 b c404040404040 88  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " c25 0 0 0 0 0 0 0 0899de02ce0e0 0 034 0 016809e32 0 0d8c5f2a0ab42 89",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "acdac579927eca7aca34c9 0 0714a4141 0 0404040 3 7 6 d e404040404040 89  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " c30 0 0 0 a 0 0 01e89a1e02cdfe1 0 01b 0 012809e32 0 0d8c1f29f493f 90",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "addad581637cc97aca34c9 0 071424141 0 0404040 3 312 d e404040404040 90  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " a f 0 0 016 0 0 01e8a96e02ce0e1 0 035 0 012809e4abdad 6c1f5568282 91",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 082826c796b7b307f 0 061404141 0 0404040 2 42f b c404040404040 91  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " a14 0 0 0 0 0 0 019899ee02ce0e1 0 042 0 016809e4a 0 0d8c4f2a0ab3e 92",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "acdac679927fca7bc935c9 0 0714a4141 0 0404040 3 73b d e404040404040 92  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " a1a 0 0 014 0 0 0238a9ce02cdfe1 0 042 0 016909e4abaab 6c1f2538381 93",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 080807ec97aca35c9 0 061514141 0 0404040 3 1 8 d e404040404040 93  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " a20 0 0 0 a 0 0 0 5899be02cdfe1 0 034 0 016909e4abc6d 6c1f2528381 94",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 080807bc97bca35c9 0 061514141 0 0404040 3 122 d e404040404040 94  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " a21 0ffffe2 0 0 02389a1e02ce0e0 0 042 0 012809e4a 0 0d8c1f2a0ce39 95",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "acdad481637bca7bca35c9 0 071424141 0 0404040 3 321 d e404040404040 95  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " b e 0 0 028 0fffffb8a9ae02ce0e1 0 034 0 016809e52bdad 6c1f6568282 96",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 082826c796a7b307f 0 061404141 0 0404040 2 631 b c404040404040 96  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " b14 0 0 01e 0 0 0 f8a98e02ce0e1 0 02d 0 010809e52baab 6c1f2568481 97",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 080807e7f7b7a3479 0 061414140 0 0404040 3 4 0 b c404040404040 97  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " b19 0 0 02b 0 0 01089a0e02ce0e1 0 024 0 012809e32 0 0dcc4f6a1473f 98",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "b0daca768e6cc96ac930ca 0 06141514a 0 0404040 2 729 d e404040404040 98  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " b24 0 0 0 d 0 0 019899ee02cdfe0 0 032 0 012809e32 0 0dcc1f5a15b3e 99",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "b1dad9565669c96aca30ca 0 061415142 0 0404040 2 327 d e404040404040 99  C",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " b2a 0ffffef 0 0 023899be02cdfe1 0 02e 0 012909e32bf6f 6c1f6538282100",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   " 0 1 0828268c969ca2fca 0 061414151 0 0404040 2 1 7 d e404040404040100  C",
+							Comment: "",
+						},
+						{
+							Name:    "COMMENT",
+							Value:   "IUE-VICAR HEADER ENDED",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "IUE-LOG STARTED",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*GEOMF   11:20Z SEP 21,'79                                            HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*********   GEOM. & PHOTOM. CORRECTED IMAGE **********                 C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "PCF C/** DATA REC. 11 1   1   1 768 8448 5 3  6.1  5.0 2536   .00000 1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "          0       1684       3374       6873       9091      10586   1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "      14371      17745      21524      25105      28500              1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "     11.000     11.000     11.000     11.000     11.000     11.000   1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "     11.000     11.000     11.000     11.000     11.000              1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "TUBE   3 SEC EHT  6.1 ITT EHT  5.0 WAVELENGTH 2536 DIFFUSER 0        1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "     C     MODE : FACTOR   .178E 00                                  1PC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*FICOR5   11:20Z SEP 21,'79                                           HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "********  DATA FROM LARGE APERTURE  ********                           C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*EXTLOW   11:20Z SEP 21,'79                                           HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "@EXTLOW: OMEGA=  90.0, HBACK=  5, DISTANCE= 11.0                       C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "        :HT=15, DC#=   1; ISN:     0 PSN      1 SIGS=  .444 SIGL=  .421C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "B 1= -.283235346667D 03 B 2=  .376096600120D 00 B 3=  .000000000000D 00C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "A 1=  .964207510446D 03 A 2= -.466539532721D 00 A 3=  .000000000000D 00C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "LINE SHIFT =   .000     SAMPLE SHIFT =   .000                          C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*SMOOTH   11:20Z SEP 21,'79                                           HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*ARCHIVE   11:20Z SEP 21,'79                                          HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*ITOE   11:20Z SEP 21,'79                                             HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "***** FILE OF MERGED EXTRACTED SPECTRA *****                           C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*** GROSS, BACKGROUND, NET & ABSOL. CALIB. NET ***                     C",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*ETOEM   11:20Z SEP 21,'79                                            HC",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "*ARCHIVE   11:20Z SEP 21,'79                                          HL",
+							Comment: "",
+						},
+						{
+							Name:    "HISTORY",
+							Value:   "IUE-LOG FINISHED",
+							Comment: "",
+						},
 					},
 					IMAGE_HDU,
 					8,
@@ -169,6 +1061,26 @@ var g_tables = []struct {
 							Value:   "IUE MELO",
 							Comment: "name of table (?)",
 						},
+						{
+							Name:    "",
+							Value:   "",
+							Comment: "",
+						},
+						{
+							Name:    "COMMENT",
+							Value:   "  IUE MELO file data containing G, B, N, A, & E vectors",
+							Comment: "",
+						},
+						{
+							Name:    "COMMENT",
+							Value:   "  Each row contains order number, npts, W0, deltaW, & vectors above",
+							Comment: "",
+						},
+						{
+							Name:    "",
+							Value:   "",
+							Comment: "",
+						},
 						{
 							Name:    "TFORM1",
 							Value:   "1I",
@@ -708,4 +1620,134 @@ func TestHDU(t *testing.T) {
 	}
 }
 
+func TestHDUInsertDelete(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	names := func(f *File) []string {
+		var names []string
+		for _, hdu := range f.HDUs() {
+			names = append(names, hdu.Name())
+		}
+		return names
+	}
+
+	extname := func(n string) Header {
+		return NewHeader([]Card{{"EXTNAME", n, ""}}, IMAGE_HDU, 8, []int64{2})
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			for _, n := range []string{"A", "BAD", "C"} {
+				_, err = NewImageHDU(&f, extname(n))
+				if err != nil {
+					t.Fatalf("error creating image %q: %v", n, err)
+				}
+			}
+			tbl, err := NewTable(&f, "TBL", []Column{{Name: "x", Value: float64(0)}}, BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+
+			err = f.DeleteHDU(2)
+			if err != nil {
+				t.Fatalf("error deleting HDU: %v", err)
+			}
+			err = f.DeleteHDU(0)
+			if err == nil {
+				t.Fatalf("expected an error deleting the primary HDU")
+			}
+
+			b, err := InsertImageHDU(&f, 2, extname("B"))
+			if err != nil {
+				t.Fatalf("error inserting image: %v", err)
+			}
+			data := []int8{1, 2}
+			err = b.Write(&data)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			_, err = InsertTable(&f, 1, "T0", []Column{{Name: "y", Value: int32(0)}}, BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error inserting table: %v", err)
+			}
+
+			want := []string{"PRIMARY", "T0", "A", "B", "C", "TBL"}
+			if !reflect.DeepEqual(names(&f), want) {
+				t.Fatalf("expected HDUs %v. got %v", want, names(&f))
+			}
+
+			// the table handle must still point at its (renumbered) HDU.
+			x := 42.0
+			err = tbl.Write(&x)
+			if err != nil {
+				t.Fatalf("error writing table: %v", err)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			want := []string{"PRIMARY", "T0", "A", "B", "C", "TBL"}
+			if !reflect.DeepEqual(names(&f), want) {
+				t.Fatalf("expected HDUs %v. got %v", want, names(&f))
+			}
+
+			data := make([]int8, 2)
+			err = f.HDU(3).Data(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !reflect.DeepEqual(data, []int8{1, 2}) {
+				t.Fatalf("expected image %v. got %v", []int8{1, 2}, data)
+			}
+
+			tbl := f.HDU(5).(*Table)
+			var x []float64
+			err = tbl.ReadColumn(0, 0, tbl.NumRows(), &x)
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			if !reflect.DeepEqual(x, []float64{42}) {
+				t.Fatalf("expected column %v. got %v", []float64{42}, x)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
 // EOF
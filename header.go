@@ -5,6 +5,7 @@ package cfitsio
 import "C"
 import (
 	"fmt"
+	"strings"
 	"unsafe"
 )
 
@@ -41,12 +42,19 @@ func NewDefaultHeader() Header {
 	)
 }
 
+// AddComment appends a COMMENT Card with text v to this Header.
 func (h *Header) AddComment(v string) {
-	panic("not implemented")
+	h.Append(Card{Name: "COMMENT", Value: v})
 }
 
+// AddHistory appends a HISTORY Card with text v to this Header.
 func (h *Header) AddHistory(v string) {
-	panic("not implemented")
+	h.Append(Card{Name: "HISTORY", Value: v})
+}
+
+// AddBlank appends a commentary Card with a blank keyword and text v to this Header.
+func (h *Header) AddBlank(v string) {
+	h.Append(Card{Name: "", Value: v})
 }
 
 // Append appends a set of Cards to this Header
@@ -75,22 +83,65 @@ func (h *Header) Get(n string) *Card {
 	return nil
 }
 
+// scaling returns the values of the scale and zero keywords (e.g. BSCALE and BZERO),
+// defaulting to 1 and 0.
+func (h *Header) scaling(scaleKey, zeroKey string) (float64, float64, error) {
+	scale, zero := 1.0, 0.0
+	var err error
+	if card := h.Get(scaleKey); card != nil {
+		scale, err = toFloat64(card.Value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("cfitsio: invalid %s value: %v", scaleKey, err)
+		}
+	}
+	if card := h.Get(zeroKey); card != nil {
+		zero, err = toFloat64(card.Value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("cfitsio: invalid %s value: %v", zeroKey, err)
+		}
+	}
+	return scale, zero, nil
+}
+
 // Comment returns the whole comment string for this Header.
+// The text of each COMMENT Card is on its own line.
 func (h *Header) Comment() string {
-	card := h.Get("COMMENT")
-	if card != nil {
-		return card.Value.(string)
-	}
-	return ""
+	return strings.Join(h.Comments(), "\n")
+}
+
+// Comments returns the text of all the COMMENT Cards of this Header, in order.
+func (h *Header) Comments() []string {
+	return h.commentary("COMMENT")
 }
 
 // History returns the whole history string for this Header.
+// The text of each HISTORY Card is on its own line.
 func (h *Header) History() string {
-	card := h.Get("HISTORY")
-	if card != nil {
-		return card.Value.(string)
+	return strings.Join(h.Histories(), "\n")
+}
+
+// Histories returns the text of all the HISTORY Cards of this Header, in order.
+func (h *Header) Histories() []string {
+	return h.commentary("HISTORY")
+}
+
+// Blanks returns the text of all the commentary Cards with a blank keyword, in order.
+func (h *Header) Blanks() []string {
+	return h.commentary("")
+}
+
+// commentary returns the text of all the commentary Cards with name n.
+func (h *Header) commentary(n string) []string {
+	var txt []string
+	for i := range h.slice {
+		card := &h.slice[i]
+		if card.Name != n {
+			continue
+		}
+		v, _ := card.Value.(string)
+		txt = append(txt, v)
 	}
-	return ""
+	return txt
 }
 
 // Bitpix returns the bitpix value.
@@ -136,6 +187,154 @@ func (h *Header) Set(n string, v interface{}, comment string) {
 	}
 }
 
+// Delete removes the Card with name n, if it exists.
+func (h *Header) Delete(n string) {
+	idx, ok := h.cards[n]
+	if !ok {
+		return
+	}
+	h.slice = append(h.slice[:idx], h.slice[idx+1:]...)
+	h.cards = make(map[string]int, len(h.slice))
+	for i := range h.slice {
+		h.cards[h.slice[i].Name] = i
+	}
+}
+
+// headerEditor is implemented by HDUs whose header can be modified in place.
+type headerEditor interface {
+	seekWrite() error
+	reload() error
+}
+
+// editHeader moves to hdu, applies edit to the file and reloads what hdu caches from its header.
+func editHeader(f *File, hdu headerEditor, edit func(f *File) error) error {
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+	err = edit(f)
+	if err != nil {
+		return err
+	}
+	return hdu.reload()
+}
+
+// updateKey sets the value and comment of the Card with name n in the current HDU of file f.
+func updateKey(n string, v interface{}, comment string) func(f *File) error {
+	return func(f *File) error {
+		return writeCards(f, []Card{{Name: n, Value: v, Comment: comment}})
+	}
+}
+
+// UpdateKey sets the value and comment of the Card with name n, in the file and in the cached Header.
+// The Card is appended if it doesn't exist. COMMENT and HISTORY Cards are always appended.
+func (hdu *ImageHDU) UpdateKey(n string, v interface{}, comment string) error {
+	return editHeader(hdu.f, hdu, updateKey(n, v, comment))
+}
+
+// InsertKey inserts card before the i-th record (0-based) of the header, in the file and in the cached Header.
+func (hdu *ImageHDU) InsertKey(i int, card Card) error {
+	return editHeader(hdu.f, hdu, func(f *File) error { return insertKey(f, i, card) })
+}
+
+// RenameKey renames the Card with name old into name n, in the file and in the cached Header.
+func (hdu *ImageHDU) RenameKey(old, n string) error {
+	return editHeader(hdu.f, hdu, func(f *File) error { return renameKey(f, old, n) })
+}
+
+// DeleteKey deletes the Card with name n, in the file and in the cached Header.
+func (hdu *ImageHDU) DeleteKey(n string) error {
+	return editHeader(hdu.f, hdu, func(f *File) error { return deleteKey(f, n) })
+}
+
+// UpdateKey sets the value and comment of the Card with name n, in the file, in the cached Header and in the columns definition.
+// The Card is appended if it doesn't exist. COMMENT and HISTORY Cards are always appended.
+func (hdu *Table) UpdateKey(n string, v interface{}, comment string) error {
+	return editHeader(hdu.f, hdu, updateKey(n, v, comment))
+}
+
+// InsertKey inserts card before the i-th record (0-based) of the header, in the file, in the cached Header and in the columns definition.
+func (hdu *Table) InsertKey(i int, card Card) error {
+	return editHeader(hdu.f, hdu, func(f *File) error { return insertKey(f, i, card) })
+}
+
+// RenameKey renames the Card with name old into name n, in the file, in the cached Header and in the columns definition.
+func (hdu *Table) RenameKey(old, n string) error {
+	return editHeader(hdu.f, hdu, func(f *File) error { return renameKey(f, old, n) })
+}
+
+// DeleteKey deletes the Card with name n, in the file, in the cached Header and in the columns definition.
+func (hdu *Table) DeleteKey(n string) error {
+	return editHeader(hdu.f, hdu, func(f *File) error { return deleteKey(f, n) })
+}
+
+// insertKey inserts card before the i-th record (0-based) of the current HDU of file f.
+func insertKey(f *File, i int, card Card) error {
+	c_status := C.int(0)
+	c_pos := C.int(i + 1) // 0-based to 1-based index
+
+	switch card.Name {
+	case "COMMENT", "HISTORY", "":
+		v, ok := card.Value.(string)
+		if !ok {
+			return fmt.Errorf("cfitsio: invalid %q card value type (%T)", card.Name, card.Value)
+		}
+		c_card := C.CString(fmt.Sprintf("%-8s%s", card.Name, v))
+		defer C.free(unsafe.Pointer(c_card))
+		C.fits_insert_record(f.c, c_pos, c_card, &c_status)
+		return to_err(c_status)
+	}
+
+	c_name := C.CString(card.Name)
+	defer C.free(unsafe.Pointer(c_name))
+	c_card := C.CStringN(C.FLEN_CARD)
+	defer C.free(unsafe.Pointer(c_card))
+
+	C.fits_read_card(f.c, c_name, c_card, &c_status)
+	if c_status == 0 {
+		return fmt.Errorf("cfitsio: card %q already exists", card.Name)
+	}
+
+	// let CFITSIO format the record: write it at the end of the header,
+	// then move it to its final position.
+	err := writeCards(f, []Card{card})
+	if err != nil {
+		return err
+	}
+
+	c_status = 0
+	C.fits_read_card(f.c, c_name, c_card, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	C.fits_delete_key(f.c, c_name, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	C.fits_insert_record(f.c, c_pos, c_card, &c_status)
+	return to_err(c_status)
+}
+
+// renameKey renames the keyword old into n in the current HDU of file f.
+func renameKey(f *File, old, n string) error {
+	c_status := C.int(0)
+	c_old := C.CString(old)
+	defer C.free(unsafe.Pointer(c_old))
+	c_name := C.CString(n)
+	defer C.free(unsafe.Pointer(c_name))
+	C.fits_modify_name(f.c, c_old, c_name, &c_status)
+	return to_err(c_status)
+}
+
+// deleteKey deletes the keyword n from the current HDU of file f.
+func deleteKey(f *File, n string) error {
+	c_status := C.int(0)
+	c_name := C.CString(n)
+	defer C.free(unsafe.Pointer(c_name))
+	C.fits_delete_key(f.c, c_name, &c_status)
+	return to_err(c_status)
+}
+
 // readHeader returns the Header i from file f
 func readHeader(f *File, i int) (Header, error) {
 	var err error
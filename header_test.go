@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -296,4 +297,372 @@ func TestHeaderRW(t *testing.T) {
 	}
 }
 
+func TestHeaderCommentary(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	comments := []string{"first comment", "second comment"}
+	histories := []string{"created by go-cfitsio", "calibrated", "rebinned"}
+	blanks := []string{"a blank keyword card"}
+	long := strings.Repeat("0123456789", 10)
+
+	check := func(hdr Header) {
+		got := hdr.Comments()
+		if len(got) < len(comments) || !reflect.DeepEqual(got[len(got)-len(comments):], comments) {
+			t.Fatalf("expected comments %q. got %q", comments, got)
+		}
+		if !reflect.DeepEqual(hdr.Histories(), histories) {
+			t.Fatalf("expected histories %q. got %q", histories, hdr.Histories())
+		}
+		if !reflect.DeepEqual(hdr.Blanks(), blanks) {
+			t.Fatalf("expected blanks %q. got %q", blanks, hdr.Blanks())
+		}
+		if hdr.History() != strings.Join(histories, "\n") {
+			t.Fatalf("expected history %q. got %q", strings.Join(histories, "\n"), hdr.History())
+		}
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			hdr := NewDefaultHeader()
+			for _, v := range comments {
+				hdr.AddComment(v)
+			}
+			for _, v := range histories {
+				hdr.AddHistory(v)
+			}
+			for _, v := range blanks {
+				hdr.AddBlank(v)
+			}
+			check(hdr)
+
+			phdu, err := NewPrimaryHDU(&f, hdr)
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+			check(phdu.Header())
+
+			// blank keyword cards longer than 72 characters are continued on the next card.
+			img, err := NewImageHDU(&f, NewHeader([]Card{{Name: "", Value: long}}, IMAGE_HDU, 8, []int64{}))
+			if err != nil {
+				t.Fatalf("error creating image: %v", err)
+			}
+			want := []string{long[:72], long[72:]}
+			if ihdr := img.Header(); !reflect.DeepEqual(ihdr.Blanks(), want) {
+				t.Fatalf("expected blanks %q. got %q", want, ihdr.Blanks())
+			}
+
+			cards := []Card{
+				{Name: "HISTORY", Value: histories[0]},
+				{Name: "HISTORY", Value: histories[1]},
+			}
+			tbl, err := NewTable(&f, "table", []Column{{Name: "x", Value: float64(0)}}, BINARY_TBL, cards...)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			thdr := tbl.Header()
+			if !reflect.DeepEqual(thdr.Histories(), histories[:2]) {
+				t.Fatalf("expected histories %q. got %q", histories[:2], thdr.Histories())
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			check(f.HDU(0).Header())
+
+			thdr := f.HDU(2).Header()
+			if !reflect.DeepEqual(thdr.Histories(), histories[:2]) {
+				t.Fatalf("expected histories %q. got %q", histories[:2], thdr.Histories())
+			}
+		},
+		// copy
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			g, err := Create("copy.fits")
+			if err != nil {
+				t.Fatalf("error creating new file: %v", err)
+			}
+			defer g.Close()
+
+			// the COMMENT cards CFITSIO writes in primary HDUs are not duplicated,
+			// the duplicated comments of the copied header are kept.
+			hdr := f.HDU(0).Header()
+			hdr.AddComment(comments[0])
+			phdu, err := NewPrimaryHDU(&g, hdr)
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+			got := phdu.Header()
+			if !reflect.DeepEqual(got.Comments(), hdr.Comments()) {
+				t.Fatalf("expected comments %q. got %q", hdr.Comments(), got.Comments())
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestHeaderEdit(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	check := func(hdr Header) {
+		card := hdr.Get("OBSERVER")
+		if card == nil {
+			t.Fatalf("error retrieving card [OBSERVER]")
+		}
+		if card.Value != "edwin" || card.Comment != "the observer" {
+			t.Fatalf("card OBSERVER. expected [%v/%v]. got [%v/%v]", "edwin", "the observer", card.Value, card.Comment)
+		}
+		card = hdr.Get("EXPOSURE")
+		if card == nil {
+			t.Fatalf("error retrieving card [EXPOSURE]")
+		}
+		if card.Value != int64(42) {
+			t.Fatalf("card EXPOSURE. expected [%v]. got [%v](%T)", 42, card.Value, card.Value)
+		}
+		if hdr.Get("EXPTIME") != nil {
+			t.Fatalf("expected card [EXPTIME] to be renamed")
+		}
+		if hdr.Get("TO_DEL") != nil {
+			t.Fatalf("expected card [TO_DEL] to be deleted")
+		}
+		if idx := hdr.Index("INSERTED"); idx != 5 {
+			t.Fatalf("expected card [INSERTED] at index 5. got %d", idx)
+		}
+		if hdr.Get("INSERTED").Value != 3.5 {
+			t.Fatalf("card INSERTED. expected [%v]. got [%v]", 3.5, hdr.Get("INSERTED").Value)
+		}
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			hdr := NewHeader(
+				[]Card{
+					{"OBSERVER", "hubble", "the observer"},
+					{"EXPTIME", 10, "exposure time"},
+					{"TO_DEL", 1, "a card to delete"},
+				},
+				IMAGE_HDU,
+				8,
+				[]int64{},
+			)
+			phdu, err := NewPrimaryHDU(&f, hdr)
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+		},
+		// edit
+		func() {
+			f, err := Open(fname, ReadWrite)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			hdu := f.HDU(0).(*PrimaryHDU)
+			err = hdu.UpdateKey("OBSERVER", "edwin", "the observer")
+			if err != nil {
+				t.Fatalf("error updating key: %v", err)
+			}
+			err = hdu.RenameKey("EXPTIME", "EXPOSURE")
+			if err != nil {
+				t.Fatalf("error renaming key: %v", err)
+			}
+			err = hdu.UpdateKey("EXPOSURE", 42, "exposure time")
+			if err != nil {
+				t.Fatalf("error updating key: %v", err)
+			}
+			err = hdu.DeleteKey("TO_DEL")
+			if err != nil {
+				t.Fatalf("error deleting key: %v", err)
+			}
+			err = hdu.InsertKey(5, Card{"INSERTED", 3.5, "an inserted card"})
+			if err != nil {
+				t.Fatalf("error inserting key: %v", err)
+			}
+			err = hdu.InsertKey(5, Card{"INSERTED", 3.5, "an inserted card"})
+			if err == nil {
+				t.Fatalf("expected an error inserting a duplicate key")
+			}
+			err = hdu.DeleteKey("NOT_THERE")
+			if err == nil {
+				t.Fatalf("expected an error deleting a missing key")
+			}
+			err = hdu.UpdateKey("INVALID", struct{}{}, "")
+			if err == nil {
+				t.Fatalf("expected an error updating a key with an invalid value type")
+			}
+			check(hdu.Header())
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			check(f.HDU(0).Header())
+		},
+	} {
+		fct()
+	}
+}
+
+func TestHeaderEditTable(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	check := func(tbl *Table) {
+		if idx := tbl.Index("X"); idx != 0 {
+			t.Fatalf("expected column [X] at index 0. got %d", idx)
+		}
+		if idx := tbl.Index("A"); idx != -1 {
+			t.Fatalf("expected column [A] to be renamed. got index %d", idx)
+		}
+		if name := tbl.Col(0).Name; name != "X" {
+			t.Fatalf("expected column name [X]. got [%v]", name)
+		}
+		if scale := tbl.Col(1).Bscale; scale != 2 {
+			t.Fatalf("expected TSCAL2=2. got %v", scale)
+		}
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTable(
+				&f, "data",
+				[]Column{
+					{Name: "A", Value: float64(0)},
+					{Name: "B", Value: int16(0)},
+				},
+				BINARY_TBL,
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+		},
+		// edit
+		func() {
+			f, err := Open(fname, ReadWrite)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			err = tbl.UpdateKey("TTYPE1", "X", "")
+			if err != nil {
+				t.Fatalf("error updating key: %v", err)
+			}
+			err = tbl.UpdateKey("TSCAL2", 2.0, "")
+			if err != nil {
+				t.Fatalf("error updating key: %v", err)
+			}
+			check(tbl)
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			check(f.HDU(1).(*Table))
+		},
+	} {
+		fct()
+	}
+}
+
 // EOF
@@ -0,0 +1,234 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+)
+
+// HistAxis describes how a table column is binned along one axis of a histogram.
+type HistAxis struct {
+	Column string  // name of the column to bin
+	Min    float64 // lower edge of the first bin
+	Max    float64 // upper edge of the last bin. If Min == Max, TLMIN/TLMAX or the data range are used.
+	Bin    float64 // bin size. If 0, TDBIN or 1 is used.
+}
+
+// HistOptions holds the optional parameters of a histogram.
+type HistOptions struct {
+	Bitpix     int64   // BITPIX of the output image (default: 32, or -32 for weighted histograms)
+	Weight     float64 // weight of each entry (default: 1)
+	WeightCol  string  // name of the column holding the weight of each entry (overrides Weight)
+	Reciprocal bool    // use the reciprocal of the weight
+	Where      string  // row filtering expression. Only the rows for which it is true are binned.
+}
+
+// Histogram bins the columns described by axes (one to four) into a new image HDU of File out.
+// out must be another File than the one holding this table.
+// If out is empty, the histogram is written into its Primary HDU.
+// The output image carries linear WCS keywords mapping pixels to column values.
+func (hdu *Table) Histogram(out *File, axes []HistAxis, opts HistOptions) (*ImageHDU, error) {
+	naxis := len(axes)
+	if naxis < 1 || naxis > 4 {
+		return nil, fmt.Errorf("cfitsio: invalid number of histogram axes (%d)", naxis)
+	}
+	if out.c == hdu.f.c {
+		return nil, fmt.Errorf("cfitsio: Histogram needs an output File distinct from the input one")
+	}
+
+	var c_selected *C.char
+	if opts.Where != "" {
+		irows, err := hdu.findRows(opts.Where)
+		if err != nil {
+			return nil, err
+		}
+		flags := make([]C.char, hdu.NumRows()+1)
+		for _, irow := range irows {
+			flags[irow] = 1
+		}
+		c_selected = &flags[0]
+	}
+
+	c_wtcol := C.int(0)
+	if opts.WeightCol != "" {
+		icol := hdu.Index(opts.WeightCol)
+		if icol < 0 {
+			return nil, fmt.Errorf("cfitsio: no such column %q", opts.WeightCol)
+		}
+		c_wtcol = C.int(icol + 1)
+	}
+	weight := opts.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	bitpix := opts.Bitpix
+	if bitpix == 0 {
+		bitpix = 32
+		if weight != 1 || c_wtcol != 0 {
+			bitpix = -32
+		}
+	}
+
+	err := hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		c_colname [4][C.FLEN_VALUE]C.char
+		c_minname [4][C.FLEN_VALUE]C.char
+		c_maxname [4][C.FLEN_VALUE]C.char
+		c_binname [4][C.FLEN_VALUE]C.char
+		c_minin   [4]C.double
+		c_maxin   [4]C.double
+		c_binin   [4]C.double
+		c_colnum  [4]C.int
+		c_haxes   [4]C.long
+		c_amin    [4]C.double
+		c_amax    [4]C.double
+		c_binsize [4]C.double
+	)
+	for i, axis := range axes {
+		if len(axis.Column) >= C.FLEN_VALUE {
+			return nil, fmt.Errorf("cfitsio: invalid column name %q", axis.Column)
+		}
+		for j := 0; j < len(axis.Column); j++ {
+			c_colname[i][j] = C.char(axis.Column[j])
+		}
+		c_minin[i] = C.DOUBLENULLVALUE
+		c_maxin[i] = C.DOUBLENULLVALUE
+		if axis.Min != axis.Max {
+			c_minin[i] = C.double(axis.Min)
+			c_maxin[i] = C.double(axis.Max)
+		}
+		c_binin[i] = C.DOUBLENULLVALUE
+		if axis.Bin != 0 {
+			c_binin[i] = C.double(axis.Bin)
+		}
+	}
+
+	c_status := C.int(0)
+	C.fits_calc_binningd(
+		hdu.f.c, C.int(naxis), &c_colname[0], &c_minin[0], &c_maxin[0], &c_binin[0],
+		&c_minname[0], &c_maxname[0], &c_binname[0],
+		&c_colnum[0], &c_haxes[0], &c_amin[0], &c_amax[0], &c_binsize[0], &c_status,
+	)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	cards := make([]Card, 0, 5*naxis)
+	for i := 0; i < naxis; i++ {
+		wcs, err := hdu.histWCS(i+1, int(c_colnum[i])-1, float64(c_amin[i]), float64(c_binsize[i]))
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, wcs...)
+	}
+
+	dims := make([]int64, naxis)
+	for i := range dims {
+		dims[i] = int64(c_haxes[i])
+	}
+
+	var himg *ImageHDU
+	hdr := NewHeader(nil, IMAGE_HDU, bitpix, dims)
+	switch len(out.hdus) {
+	case 0:
+		phdu, err := NewPrimaryHDU(out, hdr)
+		if err != nil {
+			return nil, err
+		}
+		himg = &phdu.(*PrimaryHDU).ImageHDU
+	default:
+		himg, err = NewImageHDU(out, hdr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+	err = himg.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	c_recip := C.int(0)
+	if opts.Reciprocal {
+		c_recip = 1
+	}
+	C.fits_make_histd(
+		hdu.f.c, out.c, C.int(bitpix), C.int(naxis), &c_haxes[0], &c_colnum[0],
+		&c_amin[0], &c_amax[0], &c_binsize[0], C.double(weight), c_wtcol, c_recip,
+		c_selected, &c_status,
+	)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	err = writeCards(out, cards)
+	if err != nil {
+		return nil, err
+	}
+
+	err = himg.reloadHeader()
+	if err != nil {
+		return nil, err
+	}
+	return himg, nil
+}
+
+// histWCS returns the WCS Cards of the histogram axis iaxis (1-based), binning column icol (0-based)
+// from amin with bins of size binsize.
+// The column WCS keywords (TCTYPn, TCRPXn, TCRVLn, TCDLTn, TCUNIn) are propagated if present.
+func (hdu *Table) histWCS(iaxis, icol int, amin, binsize float64) ([]Card, error) {
+	col := &hdu.cols[icol]
+	key := func(k string) string { return fmt.Sprintf("%s%d", k, iaxis) }
+	colkey := func(k string) *Card { return hdu.header.Get(fmt.Sprintf("%s%d", k, icol+1)) }
+
+	ctype := col.Name
+	if card := colkey("TCTYP"); card != nil {
+		ctype = fmt.Sprint(card.Value)
+	}
+	cunit := col.Unit
+	if card := colkey("TCUNI"); card != nil {
+		cunit = fmt.Sprint(card.Value)
+	}
+
+	// by default, pixel 1 is centered on the middle of the first bin.
+	crpix := 1.0
+	crval := amin + 0.5*binsize
+	cdelt := binsize
+	tcrpx, tcrvl, tcdlt := colkey("TCRPX"), colkey("TCRVL"), colkey("TCDLT")
+	if tcrpx != nil && tcrvl != nil && tcdlt != nil {
+		var vals [3]float64
+		for i, card := range []*Card{tcrpx, tcrvl, tcdlt} {
+			v, err := toFloat64(card.Value)
+			if err != nil {
+				return nil, fmt.Errorf("cfitsio: invalid %s value: %v", card.Name, err)
+			}
+			vals[i] = v
+		}
+		crpix = (vals[0]-amin)/binsize + 0.5
+		crval = vals[1]
+		cdelt = vals[2] * binsize
+	}
+
+	cards := []Card{
+		{Name: key("CTYPE"), Value: ctype, Comment: "axis type"},
+		{Name: key("CRPIX"), Value: crpix, Comment: "reference pixel"},
+		{Name: key("CRVAL"), Value: crval, Comment: "coordinate value at reference pixel"},
+		{Name: key("CDELT"), Value: cdelt, Comment: "coordinate increment per pixel"},
+	}
+	if cunit != "" {
+		cards = append(cards, Card{Name: key("CUNIT"), Value: cunit, Comment: "axis unit"})
+	}
+	return cards, nil
+}
+
+// EOF
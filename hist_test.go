@@ -0,0 +1,157 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTableHistogram(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	xs := []float64{0.5, 1.5, 1.5, 2.5, 3.5, 3.5, 3.5}
+	ys := []float64{0.5, 0.5, 1.5, 1.5, 0.5, 1.5, 1.5}
+	ws := []float64{1, 2, 2, 1, 1, 1, 4}
+
+	f, err := Create("events.fits")
+	if err != nil {
+		t.Fatalf("error creating new file: %v", err)
+	}
+	defer f.Close()
+
+	phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+	if err != nil {
+		t.Fatalf("error creating PHDU: %v", err)
+	}
+	defer phdu.Close()
+
+	tbl, err := NewTable(
+		&f, "events",
+		[]Column{
+			{Name: "X", Value: float64(0), Unit: "mm"},
+			{Name: "Y", Value: float64(0)},
+			{Name: "W", Value: float64(0)},
+		},
+		BINARY_TBL,
+	)
+	if err != nil {
+		t.Fatalf("error creating table: %v", err)
+	}
+	defer tbl.Close()
+
+	for i, v := range [][]float64{xs, ys, ws} {
+		err = tbl.WriteColumn(i, 0, &v)
+		if err != nil {
+			t.Fatalf("error writing column: %v", err)
+		}
+	}
+
+	out, err := Create("hist.fits")
+	if err != nil {
+		t.Fatalf("error creating new file: %v", err)
+	}
+	defer out.Close()
+
+	// 1D histogram, in the primary HDU
+	h1, err := tbl.Histogram(&out, []HistAxis{{Column: "X", Min: 0, Max: 4, Bin: 1}}, HistOptions{})
+	if err != nil {
+		t.Fatalf("error making histogram: %v", err)
+	}
+	hdr := h1.Header()
+	if !reflect.DeepEqual(hdr.Axes(), []int64{4}) {
+		t.Fatalf("expected axes %v. got %v", []int64{4}, hdr.Axes())
+	}
+	counts := make([]int32, 4)
+	err = h1.Data(&counts)
+	if err != nil {
+		t.Fatalf("error reading histogram: %v", err)
+	}
+	if !reflect.DeepEqual(counts, []int32{1, 2, 1, 3}) {
+		t.Fatalf("expected counts %v. got %v", []int32{1, 2, 1, 3}, counts)
+	}
+	for _, ref := range []Card{
+		{Name: "CTYPE1", Value: "X"},
+		{Name: "CUNIT1", Value: "mm"},
+		{Name: "CRPIX1", Value: 1.0},
+		{Name: "CRVAL1", Value: 0.5},
+		{Name: "CDELT1", Value: 1.0},
+	} {
+		card := hdr.Get(ref.Name)
+		if card == nil {
+			t.Fatalf("missing card %q", ref.Name)
+		}
+		if card.Value != ref.Value {
+			t.Fatalf("card %q. expected %v. got %v", ref.Name, ref.Value, card.Value)
+		}
+	}
+
+	// 2D weighted histogram, with a row filter, in an image extension
+	h2, err := tbl.Histogram(
+		&out,
+		[]HistAxis{
+			{Column: "X", Min: 0, Max: 4, Bin: 2},
+			{Column: "Y", Min: 0, Max: 2, Bin: 1},
+		},
+		HistOptions{WeightCol: "W", Where: "X > 1"},
+	)
+	if err != nil {
+		t.Fatalf("error making histogram: %v", err)
+	}
+	if len(out.HDUs()) != 2 {
+		t.Fatalf("expected 2 HDUs. got %d", len(out.HDUs()))
+	}
+	hdr = h2.Header()
+	if hdr.Bitpix() != -32 {
+		t.Fatalf("expected BITPIX=-32. got %d", hdr.Bitpix())
+	}
+	sums := make([]float32, 4)
+	err = h2.Data(&sums)
+	if err != nil {
+		t.Fatalf("error reading histogram: %v", err)
+	}
+	if !reflect.DeepEqual(sums, []float32{2, 1, 2, 6}) {
+		t.Fatalf("expected sums %v. got %v", []float32{2, 1, 2, 6}, sums)
+	}
+
+	_, err = tbl.Histogram(&out, nil, HistOptions{})
+	if err == nil {
+		t.Fatalf("expected an error making a histogram without axes")
+	}
+
+	// non-numeric column WCS keywords are reported, without creating an image.
+	for _, card := range []Card{
+		{Name: "TCRPX1", Value: 1.0},
+		{Name: "TCRVL1", Value: 0.0},
+		{Name: "TCDLT1", Value: "one"},
+	} {
+		err = tbl.UpdateKey(card.Name, card.Value, "")
+		if err != nil {
+			t.Fatalf("error updating key: %v", err)
+		}
+	}
+	_, err = tbl.Histogram(&out, []HistAxis{{Column: "X", Min: 0, Max: 4, Bin: 1}}, HistOptions{})
+	if err == nil {
+		t.Fatalf("expected an error making a histogram with a non-numeric TCDLT1")
+	}
+	if len(out.HDUs()) != 2 {
+		t.Fatalf("expected 2 HDUs. got %d", len(out.HDUs()))
+	}
+}
+
+// EOF
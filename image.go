@@ -12,7 +12,11 @@ import (
 // ImageHDU is a Header-Data-Unit extension holding an image as data payload.
 type ImageHDU struct {
 	f      *File
+	id     C.int // 1-based index of this HDU in the file
 	header Header
+	raw    bool // whether pixels are exchanged without BSCALE/BZERO scaling
+
+	pristine bool // whether this HDU is unchanged since the file was opened
 }
 
 // Close closes this HDU, cleaning up cycles for the proper garbage collection.
@@ -79,6 +83,11 @@ func (hdu *ImageHDU) load(v reflect.Value) error {
 		return fmt.Errorf("cfitsio: slice length [%v] is not as expected [%v]", v.Len(), nelmts)
 	}
 
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+
 	c_start := C.LONGLONG(0)
 	c_nelmts := C.LONGLONG(nelmts)
 	c_status := C.int(0)
@@ -90,7 +99,7 @@ func (hdu *ImageHDU) load(v reflect.Value) error {
 		c_ptr = unsafe.Pointer(&data[0])
 
 	case []int8:
-		c_imgtype = C.TBYTE
+		c_imgtype = C.TSBYTE
 		c_ptr = unsafe.Pointer(&data[0])
 
 	case []int16:
@@ -155,6 +164,11 @@ func (hdu *ImageHDU) Write(data interface{}) error {
 		nelmts *= int(dim)
 	}
 
+	err = hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
 	c_start := C.LONGLONG(0)
 	c_nelmts := C.LONGLONG(nelmts)
 	c_status := C.int(0)
@@ -167,7 +181,7 @@ func (hdu *ImageHDU) Write(data interface{}) error {
 		c_ptr = unsafe.Pointer(&data[0])
 
 	case []int8:
-		c_imgtype = C.TBYTE
+		c_imgtype = C.TSBYTE
 		c_ptr = unsafe.Pointer(&data[0])
 
 	case []int16:
@@ -214,6 +228,315 @@ func (hdu *ImageHDU) Write(data interface{}) error {
 	return err
 }
 
+// ReadSubset loads the N-dimensional subsection [beg, end) of the image associated with
+// this HDU into data, which should be a pointer to a slice []T.
+// beg and end hold 0-based pixel coordinates, one per axis.
+// inc holds the sampling interval along each axis. A nil inc means 1 along each axis.
+func (hdu *ImageHDU) ReadSubset(data interface{}, beg, end, inc []int64) error {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return fmt.Errorf("%T is not addressable", data)
+	}
+
+	if inc == nil {
+		inc = make([]int64, len(beg))
+		for i := range inc {
+			inc[i] = 1
+		}
+	}
+	nelmts, err := hdu.subsetLen(beg, end, inc)
+	if err != nil {
+		return err
+	}
+	if rv.Len() != nelmts {
+		return fmt.Errorf("cfitsio: slice length [%v] is not as expected [%v]", rv.Len(), nelmts)
+	}
+
+	err = hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+
+	c_imgtype, c_ptr := imageData(rv)
+	c_fpixel, c_lpixel := subsetPixels(beg, end)
+	c_inc := make([]C.long, len(inc))
+	for i, v := range inc {
+		c_inc[i] = C.long(v)
+	}
+	c_status := C.int(0)
+
+	C.fits_read_subset(hdu.f.c, c_imgtype, &c_fpixel[0], &c_lpixel[0], &c_inc[0], nil, c_ptr, nil, &c_status)
+	return to_err(c_status)
+}
+
+// WriteSubset writes data into the N-dimensional subsection [beg, end) of the image
+// associated with this HDU.
+// data should be a pointer to a slice []T.
+// beg and end hold 0-based pixel coordinates, one per axis.
+func (hdu *ImageHDU) WriteSubset(data interface{}, beg, end []int64) error {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return fmt.Errorf("%T is not addressable", data)
+	}
+
+	nelmts, err := hdu.subsetLen(beg, end, nil)
+	if err != nil {
+		return err
+	}
+	if rv.Len() != nelmts {
+		return fmt.Errorf("cfitsio: slice length [%v] is not as expected [%v]", rv.Len(), nelmts)
+	}
+
+	err = hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_imgtype, c_ptr := imageData(rv)
+	c_fpixel, c_lpixel := subsetPixels(beg, end)
+	c_status := C.int(0)
+
+	C.fits_write_subset(hdu.f.c, c_imgtype, &c_fpixel[0], &c_lpixel[0], c_ptr, &c_status)
+	return to_err(c_status)
+}
+
+// subsetLen returns the number of pixels in the subsection [beg, end) of this image,
+// sampled every inc pixels (or every pixel if inc is nil).
+func (hdu *ImageHDU) subsetLen(beg, end, inc []int64) (int, error) {
+	axes := hdu.header.Axes()
+	if len(beg) != len(axes) || len(end) != len(axes) || (inc != nil && len(inc) != len(axes)) {
+		return 0, fmt.Errorf("cfitsio: invalid subset dimensions (image has %d axes)", len(axes))
+	}
+	nelmts := 1
+	for i, dim := range axes {
+		step := int64(1)
+		if inc != nil {
+			step = inc[i]
+		}
+		if beg[i] < 0 || end[i] > dim || beg[i] >= end[i] || step <= 0 {
+			return 0, fmt.Errorf(
+				"cfitsio: invalid subset along axis %d (beg=%d, end=%d, inc=%d, dim=%d)",
+				i, beg[i], end[i], step, dim,
+			)
+		}
+		nelmts *= int((end[i] - beg[i] + step - 1) / step)
+	}
+	return nelmts, nil
+}
+
+// subsetPixels converts the 0-based range [beg, end) into CFITSIO's 1-based,
+// inclusive, first and last pixels.
+func subsetPixels(beg, end []int64) ([]C.long, []C.long) {
+	c_fpixel := make([]C.long, len(beg))
+	c_lpixel := make([]C.long, len(end))
+	for i := range beg {
+		c_fpixel[i] = C.long(beg[i] + 1)
+		c_lpixel[i] = C.long(end[i])
+	}
+	return c_fpixel, c_lpixel
+}
+
+// imageData returns the CFITSIO datatype and the address of the first element of the slice v.
+// It panics if v holds an unsupported element type.
+func imageData(v reflect.Value) (C.int, unsafe.Pointer) {
+	var c_imgtype C.int
+	var c_ptr unsafe.Pointer
+	switch data := v.Interface().(type) {
+	case []byte:
+		c_imgtype = C.TBYTE
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int8:
+		c_imgtype = C.TSBYTE
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int16:
+		c_imgtype = C.TSHORT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint16:
+		c_imgtype = C.TUSHORT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int32:
+		c_imgtype = C.TINT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint32:
+		c_imgtype = C.TUINT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int64:
+		c_imgtype = C.TLONGLONG
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint64:
+		c_imgtype = C.TULONGLONG
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []float32:
+		c_imgtype = C.TFLOAT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []float64:
+		c_imgtype = C.TDOUBLE
+		c_ptr = unsafe.Pointer(&data[0])
+
+	default:
+		panic(fmt.Errorf("invalid image type [%T]", v.Interface()))
+	}
+	return c_imgtype, c_ptr
+}
+
+// reloadHeader reads back the Header of this HDU from the file.
+func (hdu *ImageHDU) reloadHeader() error {
+	hdr, err := readHeader(hdu.f, int(hdu.id)-1)
+	if err != nil {
+		return err
+	}
+	hdu.header = hdr
+	return nil
+}
+
+// reload reads back what this HDU caches from the file: its Header.
+func (hdu *ImageHDU) reload() error {
+	return hdu.reloadHeader()
+}
+
+func (hdu *ImageHDU) seekHDU() error {
+	c_status := C.int(0)
+	c_htype := C.int(0)
+	C.fits_movabs_hdu(hdu.f.c, hdu.id, &c_htype, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	// CFITSIO only picks up BSCALE/BZERO when it parses the header:
+	// (re)apply the scaling described by the cached Header.
+	scale, zero := 1.0, 0.0
+	if !hdu.raw {
+		var err error
+		scale, zero, err = hdu.header.scaling("BSCALE", "BZERO")
+		if err != nil {
+			return err
+		}
+	}
+	C.fits_set_bscale(hdu.f.c, C.double(scale), C.double(zero), &c_status)
+	return to_err(c_status)
+}
+
+// seekWrite moves to this image before modifying it,
+// flagging it as written since the file was opened.
+func (hdu *ImageHDU) seekWrite() error {
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+	hdu.pristine = false
+	return nil
+}
+
+// SetRaw sets whether pixels are read and written as stored in the file (raw == true),
+// or converted to physical values using the BSCALE and BZERO keywords (raw == false, the default),
+// with physical = BZERO + BSCALE*stored.
+func (hdu *ImageHDU) SetRaw(raw bool) {
+	hdu.raw = raw
+}
+
+// EquivBitpix returns the BITPIX of the physical pixel values of this image, taking BSCALE and BZERO into account.
+// e.g. a BITPIX=16 image with BSCALE=1 and BZERO=32768 holds uint16 values: EquivBitpix returns 20 (CFITSIO's USHORT_IMG).
+func (hdu *ImageHDU) EquivBitpix() (int64, error) {
+	err := hdu.seekHDU()
+	if err != nil {
+		return 0, err
+	}
+	c_status := C.int(0)
+	c_bitpix := C.int(0)
+	C.fits_get_img_equivtype(hdu.f.c, &c_bitpix, &c_status)
+	if c_status > 0 {
+		return 0, to_err(c_status)
+	}
+	return int64(c_bitpix), nil
+}
+
+// NewImageHDU creates a new image extension with Header hdr in File f.
+// The new HDU is appended after the last HDU of f.
+// It returns an error if f has no Primary HDU yet.
+func NewImageHDU(f *File, hdr Header) (*ImageHDU, error) {
+	var err error
+	var hdu *ImageHDU
+
+	mode, err := f.Mode()
+	if err != nil {
+		return hdu, err
+	}
+	if mode == ReadOnly {
+		return hdu, READONLY_FILE
+	}
+
+	nhdus := len(f.hdus)
+	if nhdus == 0 {
+		return hdu, fmt.Errorf("cfitsio: File has no Primary HDU")
+	}
+
+	naxes := len(hdr.axes)
+	c_naxes := C.int(naxes)
+	slice := (*reflect.SliceHeader)((unsafe.Pointer(&hdr.axes)))
+	c_axes := (*C.long)(unsafe.Pointer(slice.Data))
+	c_status := C.int(0)
+
+	C.fits_create_img(f.c, C.int(hdr.bitpix), c_naxes, c_axes, &c_status)
+	if c_status > 0 {
+		return hdu, to_err(c_status)
+	}
+
+	err = writeHeaderCards(f, hdr.slice)
+	if err != nil {
+		return hdu, err
+	}
+
+	ihdu, err := f.readHDU(nhdus)
+	if err != nil {
+		return hdu, err
+	}
+	f.hdus = append(f.hdus, ihdu)
+	hdu = ihdu.(*ImageHDU)
+
+	return hdu, err
+}
+
+// InsertImageHDU inserts a new image extension with Header hdr in File f,
+// before the i-th HDU (index: 0-based!).
+// The following HDUs are renumbered.
+func InsertImageHDU(f *File, i int, hdr Header) (*ImageHDU, error) {
+	err := f.insertHDU(i)
+	if err != nil {
+		return nil, err
+	}
+
+	naxes := len(hdr.axes)
+	c_naxes := C.int(naxes)
+	slice := (*reflect.SliceHeader)((unsafe.Pointer(&hdr.axes)))
+	c_axes := (*C.long)(unsafe.Pointer(slice.Data))
+	c_status := C.int(0)
+
+	C.fits_insert_img(f.c, C.int(hdr.bitpix), c_naxes, c_axes, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	err = writeHeaderCards(f, hdr.slice)
+	if err != nil {
+		return nil, err
+	}
+
+	hdu, err := f.registerHDU(i)
+	if err != nil {
+		return nil, err
+	}
+	return hdu.(*ImageHDU), nil
+}
+
 // newImageHDU returns the i-th HDU from file f.
 // if i==0, the returned ImageHDU is actually the primary HDU.
 func newImageHDU(f *File, hdr Header, i int) (hdu HDU, err error) {
@@ -223,6 +546,7 @@ func newImageHDU(f *File, hdr Header, i int) (hdu HDU, err error) {
 	default:
 		hdu = &ImageHDU{
 			f:      f,
+			id:     C.int(i + 1),
 			header: hdr,
 		}
 	}
@@ -439,4 +439,254 @@ func TestImageRW(t *testing.T) {
 	}
 }
 
+func TestImageExtensions(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	names := []string{"SCI", "VAR", "MASK"}
+	images := [][]float64{
+		{0, 1, 2, 3, 4, 5},
+		{10, 11, 12, 13, 14, 15},
+		{20, 21, 22, 23, 24, 25},
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			_, err = NewImageHDU(&f, NewHeader(nil, IMAGE_HDU, -64, []int64{3, 2}))
+			if err == nil {
+				t.Fatalf("expected an error creating an image extension without a primary HDU")
+			}
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			for i, name := range names {
+				hdr := NewHeader(
+					[]Card{{"EXTNAME", name, "extension name"}},
+					IMAGE_HDU,
+					-64,
+					[]int64{3, 2},
+				)
+				hdu, err := NewImageHDU(&f, hdr)
+				if err != nil {
+					t.Fatalf("error creating image extension %q: %v", name, err)
+				}
+				defer hdu.Close()
+
+				if hdu.Name() != name {
+					t.Fatalf("expected EXTNAME==%q. got %q", name, hdu.Name())
+				}
+
+				err = hdu.Write(&images[i])
+				if err != nil {
+					t.Fatalf("error writing image %q: %v", name, err)
+				}
+			}
+
+			if len(f.HDUs()) != len(names)+1 {
+				t.Fatalf("expected %d HDUs. got %d", len(names)+1, len(f.HDUs()))
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			if len(f.HDUs()) != len(names)+1 {
+				t.Fatalf("expected %d HDUs. got %d", len(names)+1, len(f.HDUs()))
+			}
+
+			// read in reverse order to exercise HDU seeking.
+			for i := len(names) - 1; i >= 0; i-- {
+				hdu := f.HDU(i + 1)
+				if hdu.Name() != names[i] {
+					t.Fatalf("expected EXTNAME==%q. got %q", names[i], hdu.Name())
+				}
+				data := make([]float64, 6)
+				err = hdu.Data(&data)
+				if err != nil {
+					t.Fatalf("error reading image %q: %v", names[i], err)
+				}
+				if !reflect.DeepEqual(data, images[i]) {
+					t.Fatalf("image %q. expected %v. got %v", names[i], images[i], data)
+				}
+			}
+		},
+		// copy headers read from the file
+		func() {
+			f, err := Open(fname, ReadWrite)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			for _, i := range []int{0, 1} {
+				hdu, err := NewImageHDU(&f, f.HDU(i).Header())
+				if err != nil {
+					t.Fatalf("error copying header of hdu #%d: %v", i, err)
+				}
+				hdr := hdu.Header()
+				if hdr.Get("SIMPLE") != nil {
+					t.Fatalf("hdu #%d: unexpected SIMPLE card in image extension", i)
+				}
+				seen := make(map[string]bool)
+				for _, k := range hdr.Keys() {
+					switch k {
+					case "COMMENT", "HISTORY", "":
+						continue
+					}
+					if seen[k] {
+						t.Fatalf("hdu #%d: duplicate %s card", i, k)
+					}
+					seen[k] = true
+				}
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestImageSubset(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// a 4x3 image (NAXIS1=4, NAXIS2=3)
+	image := []int32{
+		0, 1, 2, 3,
+		4, 5, 6, 7,
+		8, 9, 10, 11,
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewHeader(nil, IMAGE_HDU, 32, []int64{4, 3}))
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			hdu := phdu.(*PrimaryHDU)
+			err = hdu.Write(&image)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			// overwrite the 2x2 block at (1,1)
+			block := []int32{50, 60, 90, 100}
+			err = hdu.WriteSubset(&block, []int64{1, 1}, []int64{3, 3})
+			if err != nil {
+				t.Fatalf("error writing subset: %v", err)
+			}
+
+			bad := []int32{1, 2, 3}
+			err = hdu.WriteSubset(&bad, []int64{1, 1}, []int64{3, 3})
+			if err == nil {
+				t.Fatalf("expected an error writing a subset with an invalid length")
+			}
+			err = hdu.WriteSubset(&block, []int64{1, 1}, []int64{5, 3})
+			if err == nil {
+				t.Fatalf("expected an error writing a subset out of bounds")
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			hdu := f.HDU(0).(*PrimaryHDU)
+			for _, table := range []struct {
+				beg, end, inc []int64
+				want          []int32
+			}{
+				{
+					beg:  []int64{0, 0},
+					end:  []int64{4, 3},
+					want: []int32{0, 1, 2, 3, 4, 50, 60, 7, 8, 90, 100, 11},
+				},
+				{
+					beg:  []int64{1, 1},
+					end:  []int64{3, 3},
+					want: []int32{50, 60, 90, 100},
+				},
+				{
+					beg:  []int64{0, 0},
+					end:  []int64{4, 3},
+					inc:  []int64{2, 2},
+					want: []int32{0, 2, 8, 100},
+				},
+				{
+					beg:  []int64{3, 0},
+					end:  []int64{4, 3},
+					want: []int32{3, 7, 11},
+				},
+			} {
+				data := make([]int32, len(table.want))
+				err = hdu.ReadSubset(&data, table.beg, table.end, table.inc)
+				if err != nil {
+					t.Fatalf("error reading subset [%v, %v): %v", table.beg, table.end, err)
+				}
+				if !reflect.DeepEqual(data, table.want) {
+					t.Fatalf("subset [%v, %v). expected %v. got %v", table.beg, table.end, table.want, data)
+				}
+			}
+		},
+	} {
+		fct()
+	}
+}
+
 // EOF
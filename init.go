@@ -1,6 +1,8 @@
 package cfitsio
 
 // #include "go-cfitsio.h"
+//
+// int go_cfitsio_register_stream_driver(void);
 import "C"
 
 func init() {
@@ -9,4 +11,10 @@ func init() {
 		err := to_err(c_status)
 		panic(err)
 	}
+
+	c_status = C.go_cfitsio_register_stream_driver()
+	if c_status > 0 {
+		err := to_err(c_status)
+		panic(err)
+	}
 }
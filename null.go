@@ -0,0 +1,370 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// DataNulls loads the image data associated with this HDU into data, which should
+// be a pointer to a slice []T.
+// It returns a mask, parallel to data, flagging the undefined pixels (BLANK or NaN).
+func (hdu *ImageHDU) DataNulls(data interface{}) ([]bool, error) {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return nil, fmt.Errorf("%T is not addressable", data)
+	}
+
+	nelmts, err := hdu.imageLen(rv)
+	if err != nil || nelmts == 0 {
+		return nil, err
+	}
+
+	err = hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	c_imgtype, c_ptr := imageData(rv)
+	c_mask := make([]C.char, nelmts)
+	c_anynul := C.int(0)
+	c_status := C.int(0)
+	C.fits_read_imgnull(hdu.f.c, c_imgtype, 1, C.LONGLONG(nelmts), c_ptr, &c_mask[0], &c_anynul, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+	return nullMask(c_mask), nil
+}
+
+// DataNullValue loads the image data associated with this HDU into data, which should
+// be a pointer to a slice []T.
+// Undefined pixels (BLANK or NaN) are replaced by null, which should be convertible to T.
+// null must not be zero: CFITSIO interprets a zero null value as "do not check for
+// undefined pixels". Use DataNulls to read images whose undefined pixels should read as zero.
+// DataNullValue returns whether any pixel was undefined.
+func (hdu *ImageHDU) DataNullValue(data interface{}, null interface{}) (bool, error) {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return false, fmt.Errorf("%T is not addressable", data)
+	}
+
+	nelmts, err := hdu.imageLen(rv)
+	if err != nil || nelmts == 0 {
+		return false, err
+	}
+
+	c_null, err := readNullValue(rv.Type().Elem(), null)
+	if err != nil {
+		return false, err
+	}
+
+	err = hdu.seekHDU()
+	if err != nil {
+		return false, err
+	}
+
+	c_imgtype, c_ptr := imageData(rv)
+	c_anynul := C.int(0)
+	c_status := C.int(0)
+	C.fits_read_img(hdu.f.c, c_imgtype, 1, C.LONGLONG(nelmts), c_null, c_ptr, &c_anynul, &c_status)
+	if c_status > 0 {
+		return false, to_err(c_status)
+	}
+	return c_anynul != 0, nil
+}
+
+// WriteNull writes the image to disk, like Write, flagging as undefined all the pixels equal to null.
+// Undefined pixels of integer images are written with the value of the BLANK keyword,
+// which must be present in the Header.
+// Undefined pixels of floating point images are written as NaN.
+func (hdu *ImageHDU) WriteNull(data interface{}, null interface{}) error {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return fmt.Errorf("%T is not addressable", data)
+	}
+
+	nelmts, err := hdu.imageLen(rv)
+	if err != nil || nelmts == 0 {
+		return err
+	}
+
+	c_null, err := nullValue(rv.Type().Elem(), null)
+	if err != nil {
+		return err
+	}
+
+	err = hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_imgtype, c_ptr := imageData(rv)
+	c_status := C.int(0)
+	C.fits_write_imgnull(hdu.f.c, c_imgtype, 1, C.LONGLONG(nelmts), c_ptr, c_null, &c_status)
+	return to_err(c_status)
+}
+
+// imageLen returns the number of pixels of this image, checking it matches the length of the slice v.
+func (hdu *ImageHDU) imageLen(v reflect.Value) (int, error) {
+	axes := hdu.header.Axes()
+	if len(axes) == 0 {
+		return 0, nil
+	}
+	nelmts := 1
+	for _, dim := range axes {
+		nelmts *= int(dim)
+	}
+	if v.Len() != nelmts {
+		return 0, fmt.Errorf("cfitsio: slice length [%v] is not as expected [%v]", v.Len(), nelmts)
+	}
+	return nelmts, nil
+}
+
+// ReadColumnNulls reads len(*data) elements of column icol, starting at row irow, into data,
+// which should be a pointer to a slice []T.
+// Reading continues on the following rows once the elements of row irow are exhausted.
+// It returns a mask, parallel to data, flagging the undefined elements (TNULL or NaN).
+// icol and irow are 0-based indices.
+func (hdu *Table) ReadColumnNulls(icol int, irow int64, data interface{}) ([]bool, error) {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return nil, fmt.Errorf("%T is not addressable", data)
+	}
+	if rv.Len() == 0 {
+		return nil, nil
+	}
+
+	err := hdu.seekColumn(icol)
+	if err != nil {
+		return nil, err
+	}
+
+	c_type, c_ptr, done, err := columnData(rv)
+	if err != nil {
+		return nil, err
+	}
+	c_mask := make([]C.char, rv.Len())
+	c_anynul := C.int(0)
+	c_status := C.int(0)
+	C.fits_read_colnull(
+		hdu.f.c, c_type, C.int(icol+1), C.LONGLONG(irow+1), 1, C.LONGLONG(rv.Len()),
+		c_ptr, &c_mask[0], &c_anynul, &c_status,
+	)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+	done()
+	return nullMask(c_mask), nil
+}
+
+// ReadColumnNullValue reads len(*data) elements of column icol, starting at row irow, into data,
+// which should be a pointer to a slice []T.
+// Undefined elements (TNULL or NaN) are replaced by null, which should be convertible to T.
+// null must not be zero: CFITSIO interprets a zero null value as "do not check for
+// undefined elements". Use ReadColumnNulls to read columns whose undefined elements should read as zero.
+// ReadColumnNullValue returns whether any element was undefined.
+// icol and irow are 0-based indices.
+func (hdu *Table) ReadColumnNullValue(icol int, irow int64, data interface{}, null interface{}) (bool, error) {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return false, fmt.Errorf("%T is not addressable", data)
+	}
+	if rv.Len() == 0 {
+		return false, nil
+	}
+
+	c_null, err := readNullValue(rv.Type().Elem(), null)
+	if err != nil {
+		return false, err
+	}
+
+	err = hdu.seekColumn(icol)
+	if err != nil {
+		return false, err
+	}
+
+	c_type, c_ptr, done, err := columnData(rv)
+	if err != nil {
+		return false, err
+	}
+	c_anynul := C.int(0)
+	c_status := C.int(0)
+	C.fits_read_col(
+		hdu.f.c, c_type, C.int(icol+1), C.LONGLONG(irow+1), 1, C.LONGLONG(rv.Len()),
+		c_null, c_ptr, &c_anynul, &c_status,
+	)
+	if c_status > 0 {
+		return false, to_err(c_status)
+	}
+	done()
+	return c_anynul != 0, nil
+}
+
+// WriteColumnNull writes the elements of data, which should be a pointer to a slice []T,
+// into column icol, starting at row irow, flagging as undefined all the elements equal to null.
+// Undefined elements of integer columns are written with the value of the TNULL keyword,
+// which must be present in the Header.
+// Undefined elements of floating point columns are written as NaN.
+// icol and irow are 0-based indices.
+func (hdu *Table) WriteColumnNull(icol int, irow int64, data interface{}, null interface{}) error {
+	rv := reflect.ValueOf(data).Elem()
+	if !rv.CanAddr() {
+		return fmt.Errorf("%T is not addressable", data)
+	}
+	if rv.Len() == 0 {
+		return nil
+	}
+
+	c_null, err := nullValue(rv.Type().Elem(), null)
+	if err != nil {
+		return err
+	}
+
+	err = hdu.seekWriteColumn(icol)
+	if err != nil {
+		return err
+	}
+	defer hdu.updateNumRows()
+
+	c_type, c_ptr, _, err := columnData(rv)
+	if err != nil {
+		return err
+	}
+	c_status := C.int(0)
+	C.fits_write_colnull(
+		hdu.f.c, c_type, C.int(icol+1), C.LONGLONG(irow+1), 1, C.LONGLONG(rv.Len()),
+		c_ptr, c_null, &c_status,
+	)
+	return to_err(c_status)
+}
+
+// columnData returns the CFITSIO datatype and the address of the first element of the slice v.
+// Slices of bool are exchanged with CFITSIO through a temporary buffer: done copies it back into v.
+func columnData(v reflect.Value) (C.int, unsafe.Pointer, func(), error) {
+	var c_type C.int
+	var c_ptr unsafe.Pointer
+	done := func() {}
+	switch data := v.Interface().(type) {
+	case []bool:
+		c_type = C.TLOGICAL
+		buf := make([]C.char, len(data))
+		for i, b := range data {
+			if b {
+				buf[i] = 1
+			}
+		}
+		c_ptr = unsafe.Pointer(&buf[0])
+		done = func() {
+			for i, c := range buf {
+				data[i] = c == 1
+			}
+		}
+
+	case []uint8:
+		c_type = C.TBYTE
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint16:
+		c_type = C.TUSHORT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint32:
+		c_type = C.TUINT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint64:
+		c_type = C.TULONG
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []uint:
+		c_type = C.TULONG
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int8:
+		c_type = C.TSBYTE
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int16:
+		c_type = C.TSHORT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int32:
+		c_type = C.TINT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int64:
+		c_type = C.TLONG
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []int:
+		c_type = C.TLONG
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []float32:
+		c_type = C.TFLOAT
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []float64:
+		c_type = C.TDOUBLE
+		c_ptr = unsafe.Pointer(&data[0])
+
+	case []complex64:
+		c_type = C.TCOMPLEX
+		c_ptr = unsafe.Pointer(&data[0]) // FIXME: assumes same memory layout than C
+
+	case []complex128:
+		c_type = C.TDBLCOMPLEX
+		c_ptr = unsafe.Pointer(&data[0]) // FIXME: assumes same memory layout than C
+
+	default:
+		return c_type, c_ptr, done, fmt.Errorf("cfitsio: invalid column data type [%T]", v.Interface())
+	}
+	return c_type, c_ptr, done, nil
+}
+
+// nullValue returns a pointer to null, converted to the element type rt.
+func nullValue(rt reflect.Type, null interface{}) (unsafe.Pointer, error) {
+	nv := reflect.ValueOf(null)
+	if !nv.IsValid() || !nv.Type().ConvertibleTo(rt) {
+		return nil, fmt.Errorf("cfitsio: invalid null value type (%T) for %v elements", null, rt)
+	}
+	if rt.Kind() == reflect.Bool {
+		c_null := new(C.char)
+		if nv.Bool() {
+			*c_null = 1
+		}
+		return unsafe.Pointer(c_null), nil
+	}
+	ptr := reflect.New(rt)
+	ptr.Elem().Set(nv.Convert(rt))
+	return unsafe.Pointer(ptr.Pointer()), nil
+}
+
+// readNullValue returns a pointer to null, converted to the element type rt,
+// to be used as the substitute of undefined values when reading.
+// A zero null value is rejected as CFITSIO would disable the checking of undefined values.
+func readNullValue(rt reflect.Type, null interface{}) (unsafe.Pointer, error) {
+	c_null, err := nullValue(rt, null)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.ValueOf(null).Convert(rt).IsZero() {
+		return nil, fmt.Errorf("cfitsio: invalid zero null value (%v) for %v elements", null, rt)
+	}
+	return c_null, nil
+}
+
+// nullMask converts a CFITSIO null array into a []bool.
+func nullMask(c_mask []C.char) []bool {
+	mask := make([]bool, len(c_mask))
+	for i, c := range c_mask {
+		mask[i] = c != 0
+	}
+	return mask
+}
+
+// EOF
@@ -0,0 +1,172 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestNullValues(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	image := []int16{0, 1, -1, 3, -1, 5}
+	fimage := []float64{0, -1, 2, 3, 4, -1}
+	ivals := []int32{10, -1, 12, -1}
+	fvals := []float64{-1, 1.5, 2.5, 3.5}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(
+				&f,
+				NewHeader([]Card{{"BLANK", -999, "undefined pixel value"}}, IMAGE_HDU, 16, []int64{3, 2}),
+			)
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			err = phdu.(*PrimaryHDU).WriteNull(&image, -1)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			ihdu, err := NewImageHDU(&f, NewHeader(nil, IMAGE_HDU, -64, []int64{3, 2}))
+			if err != nil {
+				t.Fatalf("error creating image: %v", err)
+			}
+			err = ihdu.WriteNull(&fimage, -1)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			tbl, err := NewTable(
+				&f, "nulls",
+				[]Column{{Name: "i", Value: int32(0)}, {Name: "f", Value: float64(0)}},
+				BINARY_TBL,
+				Card{"TNULL1", -99, "undefined value for column i"},
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			err = tbl.WriteColumnNull(0, 0, &ivals, int32(-1))
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+			err = tbl.WriteColumnNull(1, 0, &fvals, -1)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+			if tbl.NumRows() != int64(len(ivals)) {
+				t.Fatalf("expected %d rows. got %d", len(ivals), tbl.NumRows())
+			}
+
+			err = tbl.WriteColumnNull(0, 0, &ivals, "not a number")
+			if err == nil {
+				t.Fatalf("expected an error writing a column with an invalid null value")
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			data := make([]int16, len(image))
+			mask, err := f.HDU(0).(*PrimaryHDU).DataNulls(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			want := []bool{false, false, true, false, true, false}
+			if !reflect.DeepEqual(mask, want) {
+				t.Fatalf("expected mask %v. got %v", want, mask)
+			}
+
+			anynull, err := f.HDU(0).(*PrimaryHDU).DataNullValue(&data, 42)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !anynull {
+				t.Fatalf("expected undefined pixels")
+			}
+			if !reflect.DeepEqual(data, []int16{0, 1, 42, 3, 42, 5}) {
+				t.Fatalf("unexpected image %v", data)
+			}
+
+			_, err = f.HDU(0).(*PrimaryHDU).DataNullValue(&data, 0)
+			if err == nil {
+				t.Fatalf("expected an error reading image with a zero null value")
+			}
+
+			fdata := make([]float64, len(fimage))
+			mask, err = f.HDU(1).(*ImageHDU).DataNulls(&fdata)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			want = []bool{false, true, false, false, false, true}
+			if !reflect.DeepEqual(mask, want) {
+				t.Fatalf("expected mask %v. got %v", want, mask)
+			}
+
+			tbl := f.HDU(2).(*Table)
+			ivs := make([]int32, len(ivals))
+			mask, err = tbl.ReadColumnNulls(0, 0, &ivs)
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			want = []bool{false, true, false, true}
+			if !reflect.DeepEqual(mask, want) {
+				t.Fatalf("expected mask %v. got %v", want, mask)
+			}
+
+			fvs := make([]float64, len(fvals))
+			anynull, err = tbl.ReadColumnNullValue(1, 0, &fvs, math.Inf(1))
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			if !anynull {
+				t.Fatalf("expected undefined elements")
+			}
+			if !reflect.DeepEqual(fvs, []float64{math.Inf(1), 1.5, 2.5, 3.5}) {
+				t.Fatalf("unexpected column values %v", fvs)
+			}
+
+			_, err = tbl.ReadColumnNullValue(0, 0, &ivs, int32(0))
+			if err == nil {
+				t.Fatalf("expected an error reading column with a zero null value")
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
@@ -7,6 +7,8 @@ import "C"
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"unsafe"
 )
 
@@ -40,6 +42,7 @@ func newPrimaryHDU(f *File, hdr Header) (HDU, error) {
 	hdu := &PrimaryHDU{
 		ImageHDU{
 			f:      f,
+			id:     1,
 			header: hdr,
 		},
 	}
@@ -62,8 +65,55 @@ func NewPrimaryHDU(f *File, hdr Header) (HDU, error) {
 		return nil, to_err(c_status)
 	}
 
-	for icard := range hdr.slice {
-		card := &hdr.slice[icard]
+	err = writeHeaderCards(f, hdr.slice)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.hdus) > 0 {
+		return nil, fmt.Errorf("cfitsio: File has already a Primary HDU")
+	}
+
+	hdu, err := f.readHDU(0)
+	if err != nil {
+		return nil, err
+	}
+	f.hdus = append(f.hdus, hdu)
+
+	return hdu, err
+}
+
+// isStructuralKey returns whether n is the name of a keyword describing the structure of an HDU.
+func isStructuralKey(n string) bool {
+	switch n {
+	case "SIMPLE", "EXTEND", "XTENSION", "BITPIX", "NAXIS", "PCOUNT", "GCOUNT", "END":
+		return true
+	}
+	if !strings.HasPrefix(n, "NAXIS") {
+		return false
+	}
+	_, err := strconv.Atoi(n[len("NAXIS"):])
+	return err == nil
+}
+
+// writeCards writes cards to the current HDU of file f.
+// The structural keywords (SIMPLE, XTENSION, BITPIX, NAXISn, ...) are skipped:
+// CFITSIO writes them itself when it creates the HDU.
+func writeCards(f *File, cards []Card) error {
+	for icard := range cards {
+		card := &cards[icard]
+		if isStructuralKey(card.Name) {
+			continue
+		}
+		switch card.Name {
+		case "COMMENT", "HISTORY", "":
+			err := writeCommentary(f, card)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		c_name := C.CString(card.Name)
 		defer C.free(unsafe.Pointer(c_name))
 		c_type := C.int(0)
@@ -146,27 +196,120 @@ func NewPrimaryHDU(f *File, hdr Header) (HDU, error) {
 			c_ptr = unsafe.Pointer(c_value)
 
 		default:
-			panic(fmt.Errorf("cfitsio: invalid card type (%T)", v))
+			return fmt.Errorf("cfitsio: invalid card type (%T) for %q", v, card.Name)
 		}
 
 		C.fits_update_key(f.c, c_type, c_name, c_ptr, c_comm, &c_status)
 
 		if c_status > 0 {
-			return nil, to_err(c_status)
+			return to_err(c_status)
 		}
 	}
+	return nil
+}
 
-	if len(f.hdus) > 0 {
-		return nil, fmt.Errorf("cfitsio: File has already a Primary HDU")
+// commentaryLen is the maximum length of the text of a commentary card (columns 9-80).
+const commentaryLen = 72
+
+// writeCommentary writes the commentary Card card (COMMENT, HISTORY or blank keyword)
+// to the current HDU of file f.
+func writeCommentary(f *File, card *Card) error {
+	v, ok := card.Value.(string)
+	if !ok {
+		return fmt.Errorf("cfitsio: invalid %q card value type (%T)", card.Name, card.Value)
 	}
 
-	hdu, err := f.readHDU(0)
+	c_status := C.int(0)
+	switch card.Name {
+	case "COMMENT":
+		c_value := C.CString(v)
+		defer C.free(unsafe.Pointer(c_value))
+		C.fits_write_comment(f.c, c_value, &c_status)
+
+	case "HISTORY":
+		c_value := C.CString(v)
+		defer C.free(unsafe.Pointer(c_value))
+		C.fits_write_history(f.c, c_value, &c_status)
+
+	default:
+		// blank keyword: text goes in columns 9-80.
+		// Like COMMENT and HISTORY, longer texts are continued on the next cards.
+		for {
+			n := len(v)
+			if n > commentaryLen {
+				n = commentaryLen
+			}
+			c_value := C.CString(fmt.Sprintf("%-8s%s", card.Name, v[:n]))
+			C.fits_write_record(f.c, c_value, &c_status)
+			C.free(unsafe.Pointer(c_value))
+			v = v[n:]
+			if c_status > 0 || len(v) == 0 {
+				break
+			}
+		}
+	}
+
+	return to_err(c_status)
+}
+
+// writeHeaderCards writes cards, the Cards of a Header, to the HDU just created in file f.
+// When creating an HDU, CFITSIO writes commentary cards of its own
+// (the COMMENT cards about the FITS standard of primary HDUs):
+// each of them stands for the first identical commentary Card of cards, which is not written again.
+// The other commentary Cards are all written, duplicates included.
+func writeHeaderCards(f *File, cards []Card) error {
+	written, err := readCommentary(f)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	f.hdus = append(f.hdus, hdu)
+	out := make([]Card, 0, len(cards))
+	for _, card := range cards {
+		switch card.Name {
+		case "COMMENT", "HISTORY", "":
+			if v, ok := card.Value.(string); ok {
+				key := [2]string{card.Name, v}
+				if written[key] > 0 {
+					written[key]--
+					continue
+				}
+			}
+		}
+		out = append(out, card)
+	}
+	return writeCards(f, out)
+}
 
-	return hdu, err
+// readCommentary returns the number of commentary cards of the current HDU of file f,
+// indexed by keyword and text.
+func readCommentary(f *File) (map[[2]string]int, error) {
+	c_status := C.int(0)
+	c_nkeys := C.int(0)
+	c_more := C.int(0)
+	C.fits_get_hdrspace(f.c, &c_nkeys, &c_more, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	c_card := C.CStringN(C.FLEN_CARD)
+	defer C.free(unsafe.Pointer(c_card))
+
+	cards := make(map[[2]string]int)
+	for i := 1; i <= int(c_nkeys); i++ {
+		C.fits_read_record(f.c, C.int(i), c_card, &c_status)
+		if c_status > 0 {
+			return nil, to_err(c_status)
+		}
+		rec := C.GoString(c_card)
+		if len(rec) < 8 {
+			rec = fmt.Sprintf("%-8s", rec)
+		}
+		name := strings.TrimSpace(rec[:8])
+		switch name {
+		case "COMMENT", "HISTORY", "":
+			cards[[2]string{name, strings.TrimRight(rec[8:], " ")}]++
+		}
+	}
+	return cards, nil
 }
 
 // EOF
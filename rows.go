@@ -22,17 +22,22 @@ import (
 //
 type Rows struct {
 	table  *Table
-	cols   []int // list of (active) column indices
-	i      int64 // number of rows iterated over
-	n      int64 // number of rows this iterator iters over
-	inc    int64 // number of rows to increment by at each iteration
-	cur    int64 // current row index
+	cols   []int   // list of (active) column indices
+	list   []int64 // list of row indices to iterate over (nil means all rows in [i, n))
+	i      int64   // number of rows iterated over
+	n      int64   // number of rows this iterator iters over
+	inc    int64   // number of rows to increment by at each iteration
+	cur    int64   // current row index
 	closed bool
 	err    error // last error
 
 	// cache of type -> slice of (struct-field-index,col-index)
 	// used by scanStruct
 	icols map[reflect.Type][][2]int
+
+	// cache of (x-column,y-column) -> WCS
+	// used by ScanWorld
+	wcs map[[2]string]*WCS
 }
 
 // Err returns the error, if any, that was encountered during iteration.
@@ -60,6 +65,12 @@ func (rows *Rows) Scan(args ...interface{}) error {
 		rows.err = err
 	}()
 
+	// the scaling of the columns is reset each time CFITSIO moves to another HDU.
+	err = rows.table.seekColumns()
+	if err != nil {
+		return err
+	}
+
 	switch len(args) {
 	case 0:
 		// special case: read everything into the cols.
@@ -157,11 +168,7 @@ func (rows *Rows) scanStruct(data interface{}) error {
 	if _, ok := rows.icols[rt]; !ok {
 		icols := make([][2]int, 0, rt.NumField())
 		for i := 0; i < rt.NumField(); i++ {
-			f := rt.Field(i)
-			n := f.Tag.Get("fits")
-			if n == "" {
-				n = f.Name
-			}
+			n, _ := fieldTag(rt.Field(i))
 			icol := rows.table.Index(n)
 			if icol >= 0 {
 				icols = append(icols, [2]int{i, icol})
@@ -190,8 +197,17 @@ func (rows *Rows) Next() bool {
 		return false
 	}
 	next := rows.i < rows.n
-	rows.cur += rows.inc
+	switch {
+	case rows.list != nil:
+		if next {
+			rows.cur = rows.list[rows.i]
+		}
+	default:
+		rows.cur += rows.inc
+	}
 	rows.i += rows.inc
+	// the table may have shrunk since this iterator was created.
+	next = next && rows.cur < rows.table.NumRows()
 	if !next {
 		rows.err = rows.Close()
 	}
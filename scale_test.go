@@ -0,0 +1,229 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestScaling(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	u16 := []uint16{0, 1, 32768, 65535}
+	i8 := []int8{-128, -1, 0, 127}
+	adc := []float64{100, 100.5, 101, 110}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			// unsigned image: CFITSIO writes BITPIX=16 and BZERO=32768.
+			phdu, err := NewPrimaryHDU(&f, NewHeader(nil, IMAGE_HDU, 20, []int64{4}))
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+			err = phdu.(*PrimaryHDU).Write(&u16)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			// the same, with explicit scaling keywords.
+			img, err := NewImageHDU(
+				&f,
+				NewHeader(
+					[]Card{
+						{Name: "BSCALE", Value: 1.0},
+						{Name: "BZERO", Value: 32768.0},
+					},
+					IMAGE_HDU, 16, []int64{4},
+				),
+			)
+			if err != nil {
+				t.Fatalf("error creating image: %v", err)
+			}
+			err = img.Write(&u16)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			// signed bytes: BITPIX=8 and BZERO=-128.
+			img, err = NewImageHDU(&f, NewHeader(nil, IMAGE_HDU, 10, []int64{4}))
+			if err != nil {
+				t.Fatalf("error creating image: %v", err)
+			}
+			err = img.Write(&i8)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			tbl, err := NewTable(
+				&f, "adc",
+				[]Column{{Name: "ADC", Value: int16(0), Bscale: 0.5, Bzero: 100}},
+				BINARY_TBL,
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+			err = tbl.WriteColumn(0, 0, &adc)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			for i := 0; i < 2; i++ {
+				var img *ImageHDU
+				switch hdu := f.HDU(i).(type) {
+				case *PrimaryHDU:
+					img = &hdu.ImageHDU
+				case *ImageHDU:
+					img = hdu
+				}
+				hdr := img.Header()
+				if hdr.Bitpix() != 16 {
+					t.Fatalf("hdu #%d: expected BITPIX=16. got %d", i, hdr.Bitpix())
+				}
+				bitpix, err := img.EquivBitpix()
+				if err != nil {
+					t.Fatalf("hdu #%d: error reading equivalent BITPIX: %v", i, err)
+				}
+				if bitpix != 20 {
+					t.Fatalf("hdu #%d: expected equivalent BITPIX=20. got %d", i, bitpix)
+				}
+
+				data := make([]uint16, len(u16))
+				err = img.Data(&data)
+				if err != nil {
+					t.Fatalf("hdu #%d: error reading image: %v", i, err)
+				}
+				if !reflect.DeepEqual(data, u16) {
+					t.Fatalf("hdu #%d: expected %v. got %v", i, u16, data)
+				}
+
+				img.SetRaw(true)
+				raw := make([]int16, len(u16))
+				err = img.Data(&raw)
+				if err != nil {
+					t.Fatalf("hdu #%d: error reading raw image: %v", i, err)
+				}
+				want := []int16{-32768, -32767, 0, 32767}
+				if !reflect.DeepEqual(raw, want) {
+					t.Fatalf("hdu #%d: expected raw %v. got %v", i, want, raw)
+				}
+				img.SetRaw(false)
+			}
+
+			img := f.HDU(2).(*ImageHDU)
+			data := make([]int8, len(i8))
+			err = img.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			if !reflect.DeepEqual(data, i8) {
+				t.Fatalf("expected %v. got %v", i8, data)
+			}
+
+			tbl := f.HDU(3).(*Table)
+			if tbl.Col(0).Bscale != 0.5 || tbl.Col(0).Bzero != 100 {
+				t.Fatalf("unexpected scaling: TSCAL=%v TZERO=%v", tbl.Col(0).Bscale, tbl.Col(0).Bzero)
+			}
+			var vs []float64
+			err = tbl.ReadColumn(0, 0, tbl.NumRows(), &vs)
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			if !reflect.DeepEqual(vs, adc) {
+				t.Fatalf("expected %v. got %v", adc, vs)
+			}
+
+			// the scaling is set up again after moving to another HDU.
+			rows, err := tbl.Read(3, 4)
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			err = img.Data(&data)
+			if err != nil {
+				t.Fatalf("error reading image: %v", err)
+			}
+			var x float64
+			for rows.Next() {
+				err = rows.Scan(&x)
+				if err != nil {
+					t.Fatalf("error reading cell: %v", err)
+				}
+			}
+			rows.Close()
+			if x != adc[3] {
+				t.Fatalf("expected cell %v. got %v", adc[3], x)
+			}
+
+			err = tbl.SetRaw(0, true)
+			if err != nil {
+				t.Fatalf("error disabling scaling: %v", err)
+			}
+			var raw []int16
+			err = tbl.ReadColumn(0, 0, tbl.NumRows(), &raw)
+			if err != nil {
+				t.Fatalf("error reading raw column: %v", err)
+			}
+			if !reflect.DeepEqual(raw, []int16{0, 1, 2, 20}) {
+				t.Fatalf("expected raw %v. got %v", []int16{0, 1, 2, 20}, raw)
+			}
+
+			// the raw flags survive the reload of the columns definition.
+			err = tbl.reload()
+			if err != nil {
+				t.Fatalf("error reloading table: %v", err)
+			}
+			rows, err = tbl.Read(3, 4)
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			defer rows.Close()
+			var v int16
+			for rows.Next() {
+				err = rows.Scan(&v)
+				if err != nil {
+					t.Fatalf("error reading raw cell: %v", err)
+				}
+			}
+			if v != 20 {
+				t.Fatalf("expected raw cell 20. got %d", v)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
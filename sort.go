@@ -0,0 +1,222 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SortKey describes a column to sort a Table by.
+type SortKey struct {
+	Column     string // name of the column
+	Descending bool   // sort in descending order
+}
+
+// Sort sorts the rows of the table according to keys, the first key being the primary one.
+// Rows comparing equal keep their relative order.
+// NaN values sort after all the other values, in ascending and descending order.
+//
+// If the File was opened ReadWrite, the rows are physically reordered and the returned
+// iterator goes over all the rows of the (sorted) table.
+// Otherwise, the file is left untouched and the returned iterator goes over the rows in sorted order.
+func (hdu *Table) Sort(keys ...SortKey) (*Rows, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("cfitsio: Table.Sort needs at least one key")
+	}
+
+	nrows := hdu.NumRows()
+	perm := make([]int64, nrows)
+	for i := range perm {
+		perm[i] = int64(i)
+	}
+
+	cmps := make([]func(i, j int64) int, len(keys))
+	for k, key := range keys {
+		cmp, err := hdu.sortKey(key)
+		if err != nil {
+			return nil, err
+		}
+		cmps[k] = cmp
+	}
+
+	sort.SliceStable(perm, func(i, j int) bool {
+		for _, cmp := range cmps {
+			switch c := cmp(perm[i], perm[j]); {
+			case c < 0:
+				return true
+			case c > 0:
+				return false
+			}
+		}
+		return false
+	})
+
+	mode, err := hdu.f.Mode()
+	if err != nil {
+		return nil, err
+	}
+	if mode == ReadOnly {
+		rows, err := hdu.Read(0, nrows)
+		if err != nil {
+			return nil, err
+		}
+		rows.list = perm
+		rows.i = 0
+		rows.n = int64(len(perm))
+		rows.inc = 1
+		return rows, nil
+	}
+
+	err = hdu.permuteRows(perm)
+	if err != nil {
+		return nil, err
+	}
+	return hdu.Read(0, nrows)
+}
+
+// sortKey reads the values of the column described by key and returns a function
+// comparing them for rows i and j.
+func (hdu *Table) sortKey(key SortKey) (func(i, j int64) int, error) {
+	icol := hdu.Index(key.Column)
+	if icol < 0 {
+		return nil, fmt.Errorf("cfitsio: no such column %q", key.Column)
+	}
+	col := &hdu.cols[icol]
+	sign := 1
+	if key.Descending {
+		sign = -1
+	}
+
+	switch {
+	case col.Type == TSTRING:
+		err := hdu.seekHDU()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, hdu.NumRows())
+		for irow := range values {
+			err = col.read(hdu.f, icol, int64(irow), &values[irow])
+			if err != nil {
+				return nil, err
+			}
+		}
+		return func(i, j int64) int {
+			switch {
+			case values[i] < values[j]:
+				return -sign
+			case values[i] > values[j]:
+				return +sign
+			}
+			return 0
+		}, nil
+
+	case col.Type < 0 || col.Len > 1:
+		return nil, fmt.Errorf("cfitsio: can not sort by vector column %q", col.Name)
+
+	case col.integral():
+		if !col.raw && col.Bzero == 1<<63 {
+			// unsigned 64-bit integers.
+			var values []uint64
+			err := hdu.ReadColumn(icol, 0, hdu.NumRows(), &values)
+			if err != nil {
+				return nil, err
+			}
+			return func(i, j int64) int {
+				switch {
+				case values[i] < values[j]:
+					return -sign
+				case values[i] > values[j]:
+					return +sign
+				}
+				return 0
+			}, nil
+		}
+		var values []int64
+		err := hdu.ReadColumn(icol, 0, hdu.NumRows(), &values)
+		if err != nil {
+			return nil, err
+		}
+		return func(i, j int64) int {
+			switch {
+			case values[i] < values[j]:
+				return -sign
+			case values[i] > values[j]:
+				return +sign
+			}
+			return 0
+		}, nil
+
+	default:
+		var values []float64
+		err := hdu.ReadColumn(icol, 0, hdu.NumRows(), &values)
+		if err != nil {
+			return nil, err
+		}
+		return func(i, j int64) int {
+			// NaNs (undefined values) always come last, whatever the order.
+			inan, jnan := math.IsNaN(values[i]), math.IsNaN(values[j])
+			switch {
+			case inan || jnan:
+				switch {
+				case !inan:
+					return -1
+				case !jnan:
+					return +1
+				}
+				return 0
+			case values[i] < values[j]:
+				return -sign
+			case values[i] > values[j]:
+				return +sign
+			}
+			return 0
+		}, nil
+	}
+}
+
+// integral returns whether the values of this column are integers, once scaled.
+func (col *Column) integral() bool {
+	switch col.Type {
+	case TBYTE, TSBYTE, TSHORT, TUSHORT, TINT, TUINT, TLONG, TULONG, TLONGLONG:
+	default:
+		return false
+	}
+	if col.raw {
+		return true
+	}
+	return (col.Bscale == 0 || col.Bscale == 1) && col.Bzero == math.Trunc(col.Bzero)
+}
+
+// permuteRows physically reorders the rows of the table so that the new i-th row is the old perm[i]-th row.
+func (hdu *Table) permuteRows(perm []int64) error {
+	nrows := int64(len(perm))
+	if nrows == 0 {
+		return nil
+	}
+	width := hdu.header.axes[0] // NAXIS1: number of bytes per row
+
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_status := C.int(0)
+	src := make([]byte, nrows*width)
+	C.fits_read_tblbytes(hdu.f.c, 1, 1, C.LONGLONG(len(src)), (*C.uchar)(&src[0]), &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	dst := make([]byte, len(src))
+	for i, j := range perm {
+		copy(dst[int64(i)*width:int64(i+1)*width], src[j*width:(j+1)*width])
+	}
+	C.fits_write_tblbytes(hdu.f.c, 1, 1, C.LONGLONG(len(dst)), (*C.uchar)(&dst[0]), &c_status)
+	return to_err(c_status)
+}
+
+// EOF
@@ -0,0 +1,175 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTableSort(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	type Source struct {
+		ID   int32   `fits:"ID"`
+		Name string  `fits:"NAME"`
+		Dec  float64 `fits:"DEC"`
+		Big  int64   `fits:"BIG"`
+	}
+	// BIG values differ by less than the float64 precision.
+	const big = 1 << 60
+	sources := []Source{
+		{0, "b", 10.5, big + 3},
+		{1, "a", -3.0, big + 1},
+		{2, "b", 45.0, big + 4},
+		{3, "a", 12.0, big + 2},
+		{4, "c", -3.0, big},
+		{5, "c", math.NaN(), big + 5},
+	}
+
+	scanIDs := func(rows *Rows) []int32 {
+		var ids []int32
+		for rows.Next() {
+			var src Source
+			err := rows.Scan(&src)
+			if err != nil {
+				t.Fatalf("error scanning row: %v", err)
+			}
+			ids = append(ids, src.ID)
+		}
+		err := rows.Err()
+		if err != nil {
+			t.Fatalf("rows.Err: %v", err)
+		}
+		return ids
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTableFrom(&f, "sources", &Source{}, BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			for i := range sources {
+				err = tbl.Write(&sources[i])
+				if err != nil {
+					t.Fatalf("error writing row: %v", err)
+				}
+			}
+		},
+		// sorted iterator over a read-only file
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			rows, err := tbl.Sort(SortKey{Column: "NAME"}, SortKey{Column: "DEC", Descending: true})
+			if err != nil {
+				t.Fatalf("error sorting table: %v", err)
+			}
+			want := []int32{3, 1, 2, 0, 4, 5}
+			if got := scanIDs(rows); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+
+			rows, err = tbl.Sort(SortKey{Column: "BIG"})
+			if err != nil {
+				t.Fatalf("error sorting table: %v", err)
+			}
+			want = []int32{4, 1, 3, 0, 2, 5}
+			if got := scanIDs(rows); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+
+			// the file is untouched.
+			rows, err = tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading table: %v", err)
+			}
+			want = []int32{0, 1, 2, 3, 4, 5}
+			if got := scanIDs(rows); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+
+			_, err = tbl.Sort(SortKey{Column: "NOT_THERE"})
+			if err == nil {
+				t.Fatalf("expected an error sorting by an invalid column")
+			}
+		},
+		// in-place sort
+		func() {
+			f, err := Open(fname, ReadWrite)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			rows, err := tbl.Sort(SortKey{Column: "DEC"})
+			if err != nil {
+				t.Fatalf("error sorting table: %v", err)
+			}
+			want := []int32{1, 4, 0, 3, 2, 5}
+			if got := scanIDs(rows); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			rows, err := tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading table: %v", err)
+			}
+			want := []int32{1, 4, 0, 3, 2, 5}
+			if got := scanIDs(rows); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
@@ -0,0 +1,288 @@
+package cfitsio
+
+// #include <stdlib.h>
+// #include "go-cfitsio.h"
+import "C"
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// streamPrefix is the URL prefix of the CFITSIO I/O driver backed by Go streams
+const streamPrefix = "gostream://"
+
+// stream is a FITS byte stream served to CFITSIO by the gostream:// I/O driver.
+// CFITSIO needs random access to the file, so the content is held in memory:
+// read streams are loaded when opened, write streams are written out when closed.
+type stream struct {
+	id  int       // handle of the stream in the registry
+	buf []byte    // content of the FITS file
+	pos int64     // current position in buf
+	w   io.Writer // destination of the FITS content, nil for read streams
+	err error     // last error from w
+}
+
+// g_streams is the global registry of streams, indexed by driver handle
+var g_streams = struct {
+	sync.Mutex
+	id int
+	m  map[int]*stream
+}{
+	m: make(map[int]*stream),
+}
+
+// register adds s to the registry and returns the CFITSIO file name to use for s.
+func (s *stream) register() string {
+	g_streams.Lock()
+	defer g_streams.Unlock()
+	g_streams.id++
+	s.id = g_streams.id
+	g_streams.m[s.id] = s
+	return fmt.Sprintf("%s%d", streamPrefix, s.id)
+}
+
+// unregister removes s from the registry.
+func (s *stream) unregister() {
+	g_streams.Lock()
+	defer g_streams.Unlock()
+	delete(g_streams.m, s.id)
+}
+
+// getStream returns the stream registered with handle h.
+func getStream(h C.int) *stream {
+	g_streams.Lock()
+	defer g_streams.Unlock()
+	return g_streams.m[int(h)]
+}
+
+// streamHandle returns the handle of the stream registered with file name fname.
+func streamHandle(fname *C.char) (C.int, bool) {
+	name := strings.TrimPrefix(C.GoString(fname), streamPrefix)
+	id, err := strconv.Atoi(name)
+	if err != nil {
+		return 0, false
+	}
+	g_streams.Lock()
+	defer g_streams.Unlock()
+	_, ok := g_streams.m[id]
+	return C.int(id), ok
+}
+
+// OpenReader opens a FITS file from the content of r, until EOF.
+// The whole content of r is read into memory before the file is opened.
+// OpenReader will create HDU values, loading the Header part but leaving the Data part in memory.
+// The returned File is read-only.
+func OpenReader(r io.Reader) (File, error) {
+	var f File
+	var err error
+
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return f, err
+	}
+
+	f.stream = &stream{buf: buf}
+	c_fname := C.CString(f.stream.register())
+	defer C.free(unsafe.Pointer(c_fname))
+
+	c_status := C.int(0)
+	C.ffopen(&f.c, c_fname, C.READONLY, &c_status)
+	if c_status > 0 {
+		f.stream.unregister()
+		return f, to_err(c_status)
+	}
+
+	err = f.readHDUs()
+	if err != nil {
+		c_status = 0
+		C.fits_close_file(f.c, &c_status)
+		f.stream.unregister()
+	}
+	return f, err
+}
+
+// CreateWriter creates and opens a new empty FITS file which will be written to w.
+// The complete (and correctly padded) FITS byte stream is held in memory
+// and written to w when the file is closed.
+func CreateWriter(w io.Writer) (File, error) {
+	var f File
+	var err error
+
+	f.stream = &stream{w: w}
+	c_fname := C.CString(f.stream.register())
+	defer C.free(unsafe.Pointer(c_fname))
+
+	c_status := C.int(0)
+	C.fits_create_file(&f.c, c_fname, &c_status)
+	if c_status > 0 {
+		f.stream.unregister()
+		err = to_err(c_status)
+		return f, err
+	}
+
+	f.hdus = make([]HDU, 0)
+
+	return f, err
+}
+
+//export go_cfitsio_stream_checkfile
+func go_cfitsio_stream_checkfile(urltype, infile, outfile *C.char) C.int {
+	return 0
+}
+
+//export go_cfitsio_stream_open
+func go_cfitsio_stream_open(fname *C.char, rwmode C.int, handle *C.int) C.int {
+	h, ok := streamHandle(fname)
+	if !ok {
+		return C.FILE_NOT_OPENED
+	}
+	s := getStream(h)
+	if rwmode == C.READWRITE && s.w == nil {
+		return C.READONLY_FILE
+	}
+	s.pos = 0
+	*handle = h
+	return 0
+}
+
+//export go_cfitsio_stream_create
+func go_cfitsio_stream_create(fname *C.char, handle *C.int) C.int {
+	h, ok := streamHandle(fname)
+	if !ok {
+		return C.FILE_NOT_CREATED
+	}
+	s := getStream(h)
+	s.buf = s.buf[:0]
+	s.pos = 0
+	*handle = h
+	return 0
+}
+
+//export go_cfitsio_stream_truncate
+func go_cfitsio_stream_truncate(handle C.int, size C.LONGLONG) C.int {
+	s := getStream(handle)
+	if s == nil {
+		return C.BAD_FILEPTR
+	}
+	n := int(size)
+	if n <= len(s.buf) {
+		s.buf = s.buf[:n]
+	} else {
+		s.buf = append(s.buf, make([]byte, n-len(s.buf))...)
+	}
+	s.pos = int64(n)
+	return 0
+}
+
+//export go_cfitsio_stream_close
+func go_cfitsio_stream_close(handle C.int) C.int {
+	s := getStream(handle)
+	if s == nil {
+		return C.BAD_FILEPTR
+	}
+
+	g_streams.Lock()
+	delete(g_streams.m, int(handle))
+	g_streams.Unlock()
+
+	if s.w != nil {
+		_, s.err = s.w.Write(s.buf)
+		if s.err != nil {
+			return C.WRITE_ERROR
+		}
+	}
+	return 0
+}
+
+//export go_cfitsio_stream_remove
+func go_cfitsio_stream_remove(fname *C.char) C.int {
+	h, ok := streamHandle(fname)
+	if !ok {
+		return C.FILE_NOT_OPENED
+	}
+	g_streams.Lock()
+	delete(g_streams.m, int(h))
+	g_streams.Unlock()
+	return 0
+}
+
+//export go_cfitsio_stream_size
+func go_cfitsio_stream_size(handle C.int, size *C.LONGLONG) C.int {
+	s := getStream(handle)
+	if s == nil {
+		return C.BAD_FILEPTR
+	}
+	*size = C.LONGLONG(len(s.buf))
+	return 0
+}
+
+//export go_cfitsio_stream_flush
+func go_cfitsio_stream_flush(handle C.int) C.int {
+	return 0
+}
+
+//export go_cfitsio_stream_seek
+func go_cfitsio_stream_seek(handle C.int, offset C.LONGLONG) C.int {
+	s := getStream(handle)
+	if s == nil {
+		return C.BAD_FILEPTR
+	}
+	if int64(offset) > int64(len(s.buf)) {
+		return C.END_OF_FILE
+	}
+	s.pos = int64(offset)
+	return 0
+}
+
+// cbytes returns a slice of the n bytes of the C buffer ptr, without copying them.
+// Unlike a conversion to a pointer to a fixed-size array, it handles buffers of any size.
+func cbytes(ptr unsafe.Pointer, n int64) []byte {
+	var buf []byte
+	slice := (*reflect.SliceHeader)(unsafe.Pointer(&buf))
+	slice.Data = uintptr(ptr)
+	slice.Len = int(n)
+	slice.Cap = int(n)
+	return buf
+}
+
+//export go_cfitsio_stream_read
+func go_cfitsio_stream_read(handle C.int, buffer unsafe.Pointer, nbytes C.long) C.int {
+	s := getStream(handle)
+	if s == nil {
+		return C.BAD_FILEPTR
+	}
+	n := int64(nbytes)
+	if s.pos+n > int64(len(s.buf)) {
+		return C.END_OF_FILE
+	}
+	copy(cbytes(buffer, n), s.buf[s.pos:s.pos+n])
+	s.pos += n
+	return 0
+}
+
+//export go_cfitsio_stream_write
+func go_cfitsio_stream_write(handle C.int, buffer unsafe.Pointer, nbytes C.long) C.int {
+	s := getStream(handle)
+	if s == nil {
+		return C.BAD_FILEPTR
+	}
+	if s.w == nil {
+		return C.READONLY_FILE
+	}
+	n := int64(nbytes)
+	if end := s.pos + n; end > int64(len(s.buf)) {
+		s.buf = append(s.buf, make([]byte, end-int64(len(s.buf)))...)
+	}
+	copy(s.buf[s.pos:], cbytes(buffer, n))
+	s.pos += n
+	return 0
+}
+
+// EOF
@@ -0,0 +1,198 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// strShape returns the number of characters of each string held by this string column,
+// the number of strings per row and whether CFITSIO itself splits the row into strings
+// (rAw TFORM) or if the split is described by TDIM.
+func (col *Column) strShape() (width, n int, native bool) {
+	total := col.Len
+	if col.width > total {
+		total = col.width // ASCII tables: repeat is 1
+	}
+	if total < 1 {
+		total = 1
+	}
+	switch {
+	case col.width > 0 && col.width < total:
+		return col.width, total / col.width, true
+	case len(col.Dim) >= 2 && col.Dim[0] > 0 && int(col.Dim[0]) < total:
+		return int(col.Dim[0]), total / int(col.Dim[0]), false
+	}
+	return total, 1, true
+}
+
+// isStringValue returns whether the Go type rt can hold the content of a string column.
+func isStringValue(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Slice, reflect.Array:
+		return rt.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// readStrings reads the value at column number icol and row irow of this string column into ptr,
+// a pointer to a string, a []string or a [N]string.
+// icol and irow are 0-based indices.
+func (col *Column) readStrings(f *File, icol int, irow int64, ptr interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(ptr))
+	rt := reflect.TypeOf(rv.Interface())
+
+	width, n, native := col.strShape()
+	switch rt.Kind() {
+	case reflect.String:
+		if n != 1 {
+			return fmt.Errorf("cfitsio: column %q holds %d strings per row", col.Name, n)
+		}
+	case reflect.Array:
+		if rt.Len() != n {
+			return fmt.Errorf("cfitsio: column %q holds %d strings per row (not %d)", col.Name, n, rt.Len())
+		}
+	}
+
+	c_icol := C.int(icol + 1)      // 0-based to 1-based index
+	c_irow := C.LONGLONG(irow + 1) // 0-based to 1-based index
+
+	var strs []string
+	if native {
+		c_strs := make([]*C.char, n)
+		for i := range c_strs {
+			c_strs[i] = C.CStringN(C.int(width + 1))
+			defer C.free(unsafe.Pointer(c_strs[i]))
+		}
+		c_status := C.int(0)
+		c_anynul := C.int(0)
+		C.fits_read_col(f.c, C.TSTRING, c_icol, c_irow, 1, C.LONGLONG(n), nil, unsafe.Pointer(&c_strs[0]), &c_anynul, &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+		strs = make([]string, n)
+		for i, c_str := range c_strs {
+			strs[i] = C.GoString(c_str)
+		}
+	} else {
+		// CFITSIO sees a single string: split it according to TDIM.
+		c_str := C.CStringN(C.int(width*n + 1))
+		defer C.free(unsafe.Pointer(c_str))
+		c_status := C.int(0)
+		c_anynul := C.int(0)
+		C.fits_read_col(f.c, C.TSTRING, c_icol, c_irow, 1, 1, nil, unsafe.Pointer(&c_str), &c_anynul, &c_status)
+		if c_status > 0 {
+			return to_err(c_status)
+		}
+		str := C.GoString(c_str)
+		str += strings.Repeat(" ", width*n-len(str))
+		strs = make([]string, n)
+		for i := range strs {
+			strs[i] = strings.TrimRight(str[i*width:(i+1)*width], " ")
+		}
+	}
+
+	switch rt.Kind() {
+	case reflect.String:
+		rv.Set(reflect.ValueOf(strs[0]))
+	case reflect.Slice:
+		rv.Set(reflect.ValueOf(strs))
+	case reflect.Array:
+		array := reflect.New(rt).Elem()
+		reflect.Copy(array, reflect.ValueOf(strs))
+		rv.Set(array)
+	}
+	col.Value = rv.Interface()
+	return nil
+}
+
+// writeStrings writes value, a string, a []string or a [N]string, into this string column
+// at column icol and row irow.
+// Missing strings are written as blank ones.
+// Writing more strings, or longer strings, than the column can hold is an error.
+// icol and irow are 0-based indices.
+func (col *Column) writeStrings(f *File, icol int, irow int64, value interface{}) error {
+	rv := reflect.ValueOf(value)
+
+	var strs []string
+	switch rv.Kind() {
+	case reflect.String:
+		strs = []string{rv.String()}
+	default:
+		strs = make([]string, rv.Len())
+		for i := range strs {
+			strs[i] = rv.Index(i).String()
+		}
+	}
+
+	width, n, native := col.strShape()
+	if len(strs) > n {
+		return fmt.Errorf("cfitsio: can not write %d strings into column %q (max: %d)", len(strs), col.Name, n)
+	}
+	for _, str := range strs {
+		if len(str) > width {
+			return fmt.Errorf("cfitsio: string %q too long for column %q (max: %d characters)", str, col.Name, width)
+		}
+	}
+	for len(strs) < n {
+		strs = append(strs, "")
+	}
+
+	c_icol := C.int(icol + 1)      // 0-based to 1-based index
+	c_irow := C.LONGLONG(irow + 1) // 0-based to 1-based index
+	c_status := C.int(0)
+
+	if !native {
+		// CFITSIO sees a single string: join the padded strings.
+		for i, str := range strs {
+			strs[i] = str + strings.Repeat(" ", width-len(str))
+		}
+		strs = []string{strings.Join(strs, "")}
+	}
+
+	c_strs := make([]*C.char, len(strs))
+	for i, str := range strs {
+		c_strs[i] = C.CString(str)
+		defer C.free(unsafe.Pointer(c_strs[i]))
+	}
+	C.fits_write_col(f.c, C.TSTRING, c_icol, c_irow, 1, C.LONGLONG(len(c_strs)), unsafe.Pointer(&c_strs[0]), &c_status)
+	return to_err(c_status)
+}
+
+// stringFormat returns the TFORM of a column holding strings of width characters,
+// stored in Go values of type rt.
+func stringFormat(rt reflect.Type, width int, htype HDUType) (string, error) {
+	n := 1
+	switch rt.Kind() {
+	case reflect.String:
+	case reflect.Array:
+		if rt.Elem().Kind() != reflect.String {
+			return "", fmt.Errorf("cfitsio: [%v] can not be stored in a string column", rt)
+		}
+		n = rt.Len()
+	default:
+		return "", fmt.Errorf("cfitsio: [%v] can not be stored in a fixed-width string column", rt)
+	}
+
+	switch htype {
+	case ASCII_TBL:
+		if n != 1 {
+			return "", fmt.Errorf("cfitsio: ASCII tables can not hold vectors of strings")
+		}
+		return fmt.Sprintf("A%d", width), nil
+	default:
+		if n == 1 {
+			return fmt.Sprintf("%dA", width), nil
+		}
+		return fmt.Sprintf("%dA%d", n*width, width), nil
+	}
+}
+
+// EOF
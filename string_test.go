@@ -0,0 +1,179 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTableStrings(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	type Source struct {
+		Name    string    `fits:"NAME,width=16"`
+		Filters [3]string `fits:"FILTERS,width=4"`
+		Comment string    `fits:"COMMENT"`
+	}
+	sources := []Source{
+		{Name: "M31", Filters: [3]string{"U", "B", "V"}, Comment: "Andromeda"},
+		{Name: "NGC 1068", Filters: [3]string{"R", "", "Ks"}, Comment: ""},
+	}
+	tags := [][2]string{{"abcdefgh", "ijk"}, {"x", ""}}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTableFrom(&f, "sources", &sources[0], BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			for i, want := range []string{"16A", "12A4", "80A"} {
+				if got := tbl.Col(i).Format; got != want {
+					t.Fatalf("column %d: expected format %q. got %q", i, want, got)
+				}
+			}
+			if _, ok := tbl.Col(1).Value.([]string); !ok {
+				t.Fatalf("expected a []string value. got %T", tbl.Col(1).Value)
+			}
+
+			for i := range sources {
+				err = tbl.Write(&sources[i])
+				if err != nil {
+					t.Fatalf("error writing row [%v]: %v", i, err)
+				}
+			}
+
+			long := Source{Name: "a name too long for the column"}
+			err = tbl.Write(&long)
+			if err == nil {
+				t.Fatalf("expected an error writing a too long string")
+			}
+			err = tbl.Col(1).write(tbl.f, 1, 0, []string{"U", "B", "V", "R"})
+			if err == nil {
+				t.Fatalf("expected an error writing too many strings")
+			}
+
+			// a vector of strings described by TDIM.
+			vtbl, err := NewTable(
+				&f, "tags",
+				[]Column{{Name: "TAGS", Format: "16A"}},
+				BINARY_TBL,
+				Card{Name: "TDIM1", Value: "(8,2)"},
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer vtbl.Close()
+			for irow, tag := range [][]string{{"abcdefgh", "ijk"}, {"x"}} {
+				err = vtbl.Col(0).write(vtbl.f, 0, int64(irow), tag)
+				if err != nil {
+					t.Fatalf("error writing row [%v]: %v", irow, err)
+				}
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			err = tbl.seekHDU()
+			if err != nil {
+				t.Fatalf("error moving to table: %v", err)
+			}
+			rows, err := tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			defer rows.Close()
+
+			i := 0
+			for rows.Next() {
+				var src Source
+				err = rows.Scan(&src)
+				if err != nil {
+					t.Fatalf("error scanning row [%v]: %v", i, err)
+				}
+				if !reflect.DeepEqual(src, sources[i]) {
+					t.Fatalf("row %d:\nexpected=%v\ngot=%v", i, sources[i], src)
+				}
+
+				var filters []string
+				err = tbl.Col(1).read(tbl.f, 1, int64(i), &filters)
+				if err != nil {
+					t.Fatalf("error reading filters: %v", err)
+				}
+				if !reflect.DeepEqual(filters, sources[i].Filters[:]) {
+					t.Fatalf("row %d: expected %v. got %v", i, sources[i].Filters, filters)
+				}
+
+				var filter string
+				err = tbl.Col(1).read(tbl.f, 1, int64(i), &filter)
+				if err == nil {
+					t.Fatalf("expected an error reading many strings into a string")
+				}
+				i++
+			}
+			if i != len(sources) {
+				t.Fatalf("expected %d rows. got %d", len(sources), i)
+			}
+
+			tbl = f.HDU(2).(*Table)
+			err = tbl.seekHDU()
+			if err != nil {
+				t.Fatalf("error moving to table: %v", err)
+			}
+			if !reflect.DeepEqual(tbl.Col(0).Dim, []int64{8, 2}) {
+				t.Fatalf("expected TDIM [8 2]. got %v", tbl.Col(0).Dim)
+			}
+			for irow, want := range tags {
+				var got [2]string
+				err = tbl.Col(0).read(tbl.f, 0, int64(irow), &got)
+				if err != nil {
+					t.Fatalf("error reading row [%v]: %v", irow, err)
+				}
+				if got != want {
+					t.Fatalf("row %d: expected %q. got %q", irow, want, got)
+				}
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
@@ -6,6 +6,7 @@ import "C"
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"unsafe"
@@ -161,6 +162,8 @@ type Table struct {
 	cols    []Column
 	col2idx map[string]int // associates a column name to its index
 	data    interface{}
+
+	pristine bool // whether this HDU is unchanged since the file was opened
 }
 
 func (hdu *Table) Close() error {
@@ -230,7 +233,7 @@ func (hdu *Table) Index(n string) int {
 }
 
 func (hdu *Table) readRow(irow int64) error {
-	err := hdu.seekHDU()
+	err := hdu.seekColumns()
 	if err != nil {
 		return err
 	}
@@ -249,7 +252,7 @@ func (hdu *Table) readRow(irow int64) error {
 // ReadRange has the same semantics than a `for i=0; i < max; i+=inc {...}` loop
 func (hdu *Table) ReadRange(beg, end, inc int64) (*Rows, error) {
 	var rows *Rows
-	err := hdu.seekHDU()
+	err := hdu.seekColumns()
 	if err != nil {
 		return rows, err
 	}
@@ -288,6 +291,681 @@ func (hdu *Table) Read(beg, end int64) (*Rows, error) {
 	return hdu.ReadRange(beg, end, 1)
 }
 
+// ReadColumn reads the values of column icol over the rows [beg, end) into ptr,
+// which should be a pointer to a slice []T.
+// Values of vector columns are flattened, row after row.
+// The slice is resized to hold all the values.
+// icol is a 0-based index. If end > maxrows, the reading will stop at maxrows.
+func (hdu *Table) ReadColumn(icol int, beg, end int64, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	if !rv.CanAddr() || rv.Kind() != reflect.Slice {
+		return fmt.Errorf("cfitsio: %T is not a pointer to a slice", ptr)
+	}
+	if icol < 0 || icol >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", icol)
+	}
+	col := &hdu.cols[icol]
+	if col.Type < 0 {
+		return fmt.Errorf("cfitsio: ReadColumn can not read variable length column %q", col.Name)
+	}
+
+	if end > hdu.NumRows() {
+		end = hdu.NumRows()
+	}
+	if beg < 0 {
+		beg = 0
+	}
+	nrows := end - beg
+	if nrows < 0 {
+		nrows = 0
+	}
+	repeat := int64(col.Len)
+	if repeat <= 1 {
+		repeat = 1
+	}
+	n := int(nrows * repeat)
+	if rv.Len() != n {
+		if rv.Cap() >= n {
+			rv.SetLen(n)
+		} else {
+			rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	err := hdu.seekColumn(icol)
+	if err != nil {
+		return err
+	}
+
+	c_type, c_ptr, done, err := columnData(rv)
+	if err != nil {
+		return err
+	}
+	c_anynul := C.int(0)
+	c_status := C.int(0)
+	C.fits_read_col(
+		hdu.f.c, c_type, C.int(icol+1), C.LONGLONG(beg+1), 1, C.LONGLONG(n),
+		nil, c_ptr, &c_anynul, &c_status,
+	)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	done()
+	return nil
+}
+
+// WriteColumn writes the values held by ptr, which should be a pointer to a slice []T,
+// into column icol, starting at row beg.
+// Values of vector columns are flattened, row after row.
+// The table is extended as needed: use beg=NumRows() to append rows.
+// icol and beg are 0-based indices.
+func (hdu *Table) WriteColumn(icol int, beg int64, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	if !rv.CanAddr() || rv.Kind() != reflect.Slice {
+		return fmt.Errorf("cfitsio: %T is not a pointer to a slice", ptr)
+	}
+	if icol < 0 || icol >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", icol)
+	}
+	col := &hdu.cols[icol]
+	if col.Type < 0 {
+		return fmt.Errorf("cfitsio: WriteColumn can not write variable length column %q", col.Name)
+	}
+	if rv.Len() == 0 {
+		return nil
+	}
+
+	err := hdu.seekWriteColumn(icol)
+	if err != nil {
+		return err
+	}
+	defer hdu.updateNumRows()
+
+	c_type, c_ptr, _, err := columnData(rv)
+	if err != nil {
+		return err
+	}
+	c_status := C.int(0)
+	C.fits_write_col(
+		hdu.f.c, c_type, C.int(icol+1), C.LONGLONG(beg+1), 1, C.LONGLONG(rv.Len()),
+		c_ptr, &c_status,
+	)
+	return to_err(c_status)
+}
+
+// InsertRows inserts n blank rows before the row at (0-based).
+// at == NumRows() appends the rows at the end of the table.
+func (hdu *Table) InsertRows(at, n int64) error {
+	if at < 0 || at > hdu.NumRows() {
+		return fmt.Errorf("cfitsio: invalid row index %d", at)
+	}
+
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+	defer hdu.updateNumRows()
+
+	c_status := C.int(0)
+	// CFITSIO inserts the rows after firstrow (1-based), i.e. before row at (0-based).
+	C.fits_insert_rows(hdu.f.c, C.LONGLONG(at), C.LONGLONG(n), &c_status)
+	return to_err(c_status)
+}
+
+// DeleteRows deletes n rows, starting at row beg (0-based).
+func (hdu *Table) DeleteRows(beg, n int64) error {
+	if beg < 0 || n < 0 || beg+n > hdu.NumRows() {
+		return fmt.Errorf("cfitsio: invalid rows range [%d, %d)", beg, beg+n)
+	}
+
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+	defer hdu.updateNumRows()
+
+	c_status := C.int(0)
+	C.fits_delete_rows(hdu.f.c, C.LONGLONG(beg+1), C.LONGLONG(n), &c_status)
+	return to_err(c_status)
+}
+
+// DeleteRowList deletes the rows listed in irows (0-based indices).
+func (hdu *Table) DeleteRowList(irows []int64) error {
+	if len(irows) == 0 {
+		return nil
+	}
+
+	// CFITSIO needs a list of 1-based, increasing, row numbers.
+	c_rows := make([]C.LONGLONG, len(irows))
+	for i, irow := range irows {
+		if irow < 0 || irow >= hdu.NumRows() {
+			return fmt.Errorf("cfitsio: invalid row index %d", irow)
+		}
+		c_rows[i] = C.LONGLONG(irow + 1)
+	}
+	sort.Slice(c_rows, func(i, j int) bool { return c_rows[i] < c_rows[j] })
+
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+	defer hdu.updateNumRows()
+
+	c_status := C.int(0)
+	C.fits_delete_rowlistll(hdu.f.c, &c_rows[0], C.LONGLONG(len(c_rows)), &c_status)
+	return to_err(c_status)
+}
+
+// DeleteRowRange deletes the rows listed in ranges, a comma-separated list of
+// 1-based, inclusive, row ranges (e.g. "1-10,15,20-25"), as understood by CFITSIO.
+func (hdu *Table) DeleteRowRange(ranges string) error {
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+	defer hdu.updateNumRows()
+
+	c_ranges := C.CString(ranges)
+	defer C.free(unsafe.Pointer(c_ranges))
+	c_status := C.int(0)
+	C.fits_delete_rowrange(hdu.f.c, c_ranges, &c_status)
+	return to_err(c_status)
+}
+
+// DeleteRowsWhere deletes the rows for which the boolean expression expr is true.
+// expr uses the CFITSIO row filtering syntax (e.g. "ENERGY < 0 || isnull(FLUX)").
+// It returns the number of deleted rows.
+func (hdu *Table) DeleteRowsWhere(expr string) (int64, error) {
+	irows, err := hdu.findRows(expr)
+	if err != nil {
+		return 0, err
+	}
+	err = hdu.DeleteRowList(irows)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(irows)), nil
+}
+
+// Select returns an iterator over the rows for which the boolean expression expr is true.
+// expr uses the CFITSIO row filtering syntax (e.g. "ENERGY > 100 && gtifilter()").
+func (hdu *Table) Select(expr string) (*Rows, error) {
+	irows, err := hdu.findRows(expr)
+	if err != nil {
+		return nil, err
+	}
+	err = hdu.seekColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]int, len(hdu.cols))
+	for i := range hdu.cols {
+		cols[i] = i
+	}
+
+	rows := &Rows{
+		table: hdu,
+		cols:  cols,
+		list:  irows,
+		i:     0,
+		n:     int64(len(irows)),
+		inc:   1,
+		cur:   -1,
+		err:   nil,
+		icols: make(map[reflect.Type][][2]int),
+	}
+	return rows, nil
+}
+
+// CopyWhere appends to dst the rows of this table for which the boolean expression expr is true.
+// dst must have the same columns than this table, and live in a different File.
+// If dst is this table, the rows for which expr is false are deleted.
+func (hdu *Table) CopyWhere(dst *Table, expr string) error {
+	if dst.f.c == hdu.f.c && dst.id != hdu.id {
+		return fmt.Errorf("cfitsio: CopyWhere needs dst to live in another File")
+	}
+	if dst != hdu {
+		// CFITSIO copies the rows byte for byte.
+		err := hdu.checkLayout(dst)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := dst.seekWrite()
+	if err != nil {
+		return err
+	}
+	err = hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+
+	c_expr := C.CString(expr)
+	defer C.free(unsafe.Pointer(c_expr))
+	c_status := C.int(0)
+	C.fits_select_rows(hdu.f.c, dst.f.c, c_expr, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	dst.updateNumRows()
+	return nil
+}
+
+// checkLayout returns an error if the rows of dst are not laid out like the rows of this table:
+// same width (NAXIS1) and, column by column, same type, repeat count and string width.
+func (hdu *Table) checkLayout(dst *Table) error {
+	sax := hdu.header.Axes()
+	dax := dst.header.Axes()
+	if len(sax) == 0 || len(dax) == 0 || sax[0] != dax[0] {
+		return fmt.Errorf("cfitsio: tables with different row widths (NAXIS1)")
+	}
+	if len(hdu.cols) != len(dst.cols) {
+		return fmt.Errorf(
+			"cfitsio: tables with different number of columns (%d and %d)",
+			len(hdu.cols), len(dst.cols),
+		)
+	}
+	for i := range hdu.cols {
+		scol := &hdu.cols[i]
+		dcol := &dst.cols[i]
+		if scol.Type != dcol.Type || scol.Len != dcol.Len || scol.width != dcol.width {
+			return fmt.Errorf(
+				"cfitsio: column #%d: layouts differ (%s and %s)",
+				i, scol.Format, dcol.Format,
+			)
+		}
+	}
+	return nil
+}
+
+// Calculate evaluates the arithmetic expression expr over every row of the table and
+// writes the result into the column named col.
+// The column is created with the TFORM tform (e.g. "1D") if it doesn't exist, and overwritten otherwise.
+// An empty tform lets CFITSIO infer the column format from the expression.
+func (hdu *Table) Calculate(expr, col, tform string) error {
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_expr := C.CString(expr)
+	defer C.free(unsafe.Pointer(c_expr))
+	c_col := C.CString(col)
+	defer C.free(unsafe.Pointer(c_col))
+	c_tform := C.CString(tform)
+	defer C.free(unsafe.Pointer(c_tform))
+	c_status := C.int(0)
+	C.fits_calculator(hdu.f.c, c_expr, hdu.f.c, c_col, c_tform, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	return hdu.reload()
+}
+
+// Eval evaluates the arithmetic expression expr over the rows [beg, end) and stores the
+// results into ptr, which should be a pointer to a slice []T.
+// Vector results are flattened, row after row.
+// The slice is resized to hold all the values.
+// If end > maxrows, the evaluation will stop at maxrows.
+func (hdu *Table) Eval(expr string, beg, end int64, ptr interface{}) error {
+	rv := reflect.ValueOf(ptr).Elem()
+	if !rv.CanAddr() || rv.Kind() != reflect.Slice {
+		return fmt.Errorf("cfitsio: %T is not a pointer to a slice", ptr)
+	}
+
+	if end > hdu.NumRows() {
+		end = hdu.NumRows()
+	}
+	if beg < 0 {
+		beg = 0
+	}
+	nrows := end - beg
+	if nrows < 0 {
+		nrows = 0
+	}
+
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+
+	c_expr := C.CString(expr)
+	defer C.free(unsafe.Pointer(c_expr))
+	c_type := C.int(0)
+	c_nelem := C.long(0)
+	c_naxis := C.int(0)
+	c_naxes := make([]C.long, 5)
+	c_status := C.int(0)
+	C.fits_test_expr(hdu.f.c, c_expr, C.int(len(c_naxes)), &c_type, &c_nelem, &c_naxis, &c_naxes[0], &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	if c_nelem < 0 {
+		// constant expression: one value per row.
+		c_nelem = 1
+	}
+
+	n := int(nrows) * int(c_nelem)
+	if rv.Len() != n {
+		if rv.Cap() >= n {
+			rv.SetLen(n)
+		} else {
+			rv.Set(reflect.MakeSlice(rv.Type(), n, n))
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	c_type, c_ptr, done, err := columnData(rv)
+	if err != nil {
+		return err
+	}
+	c_anynul := C.int(0)
+	C.fits_calc_rows(hdu.f.c, c_type, c_expr, C.long(beg+1), C.long(n), nil, c_ptr, &c_anynul, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	done()
+	return nil
+}
+
+// findRows returns the (0-based) indices of the rows for which the boolean expression expr is true.
+func (hdu *Table) findRows(expr string) ([]int64, error) {
+	nrows := hdu.NumRows()
+	if nrows == 0 {
+		return nil, nil
+	}
+
+	err := hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	c_expr := C.CString(expr)
+	defer C.free(unsafe.Pointer(c_expr))
+	c_ngood := C.long(0)
+	c_flags := make([]C.char, nrows)
+	c_status := C.int(0)
+	C.fits_find_rows(hdu.f.c, c_expr, 1, C.long(nrows), &c_ngood, &c_flags[0], &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	irows := make([]int64, 0, int(c_ngood))
+	for i, flag := range c_flags {
+		if flag != 0 {
+			irows = append(irows, int64(i))
+		}
+	}
+	return irows, nil
+}
+
+// InsertColumn inserts col before the i-th column (0-based) of the table.
+// The column format is inferred from col.Value if col.Format is empty.
+func (hdu *Table) InsertColumn(i int, col Column) error {
+	if i < 0 || i > len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", i)
+	}
+
+	err := col.inferFormat(hdu.Type())
+	if err != nil {
+		return err
+	}
+	col.inferDim()
+
+	err = hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_status := C.int(0)
+	c_name := C.CString(col.Name)
+	defer C.free(unsafe.Pointer(c_name))
+	c_form := C.CString(col.Format)
+	defer C.free(unsafe.Pointer(c_form))
+	C.fits_insert_col(hdu.f.c, C.int(i+1), c_name, c_form, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	if col.Unit != "" {
+		err = writeCards(hdu.f, []Card{{Name: fmt.Sprintf("TUNIT%d", i+1), Value: col.Unit}})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = writeColumnKeys(hdu.f, i, &col, hdu.Type())
+	if err != nil {
+		return err
+	}
+
+	return hdu.reload()
+}
+
+// AppendColumn appends col after the last column of the table.
+func (hdu *Table) AppendColumn(col Column) error {
+	return hdu.InsertColumn(len(hdu.cols), col)
+}
+
+// DeleteColumn deletes the i-th column (0-based) of the table.
+func (hdu *Table) DeleteColumn(i int) error {
+	if i < 0 || i >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", i)
+	}
+
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_status := C.int(0)
+	C.fits_delete_col(hdu.f.c, C.int(i+1), &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	return hdu.reload()
+}
+
+// RenameColumn renames the i-th column (0-based) of the table.
+func (hdu *Table) RenameColumn(i int, name string) error {
+	if i < 0 || i >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", i)
+	}
+
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	// a comment starting with '&' preserves the existing comment.
+	err = writeCards(hdu.f, []Card{{Name: fmt.Sprintf("TTYPE%d", i+1), Value: name, Comment: "&"}})
+	if err != nil {
+		return err
+	}
+
+	return hdu.reload()
+}
+
+// ModifyColumnFormat changes the repeat count of the i-th column (0-based) of a binary table.
+// format is the new TFORM value (e.g. "5E"): only its repeat count may differ from the current one.
+func (hdu *Table) ModifyColumnFormat(i int, format string) error {
+	if i < 0 || i >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", i)
+	}
+	if hdu.Type() != BINARY_TBL {
+		return fmt.Errorf("cfitsio: ModifyColumnFormat needs a binary table")
+	}
+
+	col := &hdu.cols[i]
+	n, code, err := parseTForm(format)
+	if err != nil {
+		return err
+	}
+	_, old, err := parseTForm(col.Format)
+	if err != nil {
+		return err
+	}
+	if code != old {
+		return fmt.Errorf("cfitsio: can not change format of column %q from %q to %q", col.Name, col.Format, format)
+	}
+
+	err = hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+
+	c_status := C.int(0)
+	C.fits_modify_vector_len(hdu.f.c, C.int(i+1), C.LONGLONG(n), &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+
+	return hdu.reload()
+}
+
+// parseTForm splits a binary table TFORM value into its repeat count and its data type part.
+func parseTForm(format string) (int64, string, error) {
+	format = strings.TrimSpace(format)
+	i := 0
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	if i == len(format) {
+		return 0, "", fmt.Errorf("cfitsio: invalid column format %q", format)
+	}
+	if i == 0 {
+		return 1, format, nil
+	}
+	n, err := strconv.ParseInt(format[:i], 10, 64)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, format[i:], nil
+}
+
+// reload reads back the Header and the columns definition of this table from the file.
+func (hdu *Table) reload() error {
+	hdr, err := readHeader(hdu.f, int(hdu.id)-1)
+	if err != nil {
+		return err
+	}
+	table, err := newTable(hdu.f, hdr, int(hdu.id)-1)
+	if err != nil {
+		return err
+	}
+
+	// keep the raw flags of the columns still present, and the written state.
+	raw := make(map[string]bool, len(hdu.cols))
+	for _, col := range hdu.cols {
+		if col.raw {
+			raw[col.Name] = true
+		}
+	}
+	pristine := hdu.pristine
+	*hdu = *table.(*Table)
+	hdu.pristine = pristine
+	for icol := range hdu.cols {
+		hdu.cols[icol].raw = raw[hdu.cols[icol].Name]
+	}
+	hdu.attachCols()
+	return nil
+}
+
+// seekWrite moves to this table before modifying it,
+// flagging it as written since the file was opened.
+func (hdu *Table) seekWrite() error {
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+	hdu.pristine = false
+	return nil
+}
+
+// seekWriteColumn is seekColumn, before modifying column icol (0-based).
+func (hdu *Table) seekWriteColumn(icol int) error {
+	err := hdu.seekColumn(icol)
+	if err != nil {
+		return err
+	}
+	hdu.pristine = false
+	return nil
+}
+
+// seekWriteColumns is seekColumns, before writing rows.
+func (hdu *Table) seekWriteColumns() error {
+	err := hdu.seekColumns()
+	if err != nil {
+		return err
+	}
+	hdu.pristine = false
+	return nil
+}
+
+// seekColumn moves to this table and sets up the scaling of column icol (0-based).
+func (hdu *Table) seekColumn(icol int) error {
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+	if icol < 0 || icol >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", icol)
+	}
+	return hdu.cols[icol].setScaling(hdu.f, icol)
+}
+
+// seekColumns moves to this table and sets up the scaling of all its columns,
+// before iterating over its rows.
+func (hdu *Table) seekColumns() error {
+	err := hdu.seekHDU()
+	if err != nil {
+		return err
+	}
+	for icol := range hdu.cols {
+		err = hdu.cols[icol].setScaling(hdu.f, icol)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetRaw sets whether the values of column icol (0-based) are read and written as stored in the file (raw == true),
+// or converted to physical values using its TSCAL and TZERO keywords (raw == false, the default),
+// with physical = TZERO + TSCAL*stored.
+// It applies to the row iterators and column reads and writes started afterwards.
+func (hdu *Table) SetRaw(icol int, raw bool) error {
+	if icol < 0 || icol >= len(hdu.cols) {
+		return fmt.Errorf("cfitsio: invalid column index %d", icol)
+	}
+	hdu.cols[icol].raw = raw
+	return nil
+}
+
+// CompressHeap rewrites the heap of this binary table, removing the space left unused
+// by rewritten or deleted variable-length arrays.
+func (hdu *Table) CompressHeap() error {
+	err := hdu.seekWrite()
+	if err != nil {
+		return err
+	}
+	c_status := C.int(0)
+	C.fits_compress_heap(hdu.f.c, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	return hdu.reloadHeader()
+}
+
 func (hdu *Table) seekHDU() error {
 	c_status := C.int(0)
 	c_htype := C.int(0)
@@ -298,6 +976,16 @@ func (hdu *Table) seekHDU() error {
 	return nil
 }
 
+// reloadHeader reads back the Header of this HDU from the file.
+func (hdu *Table) reloadHeader() error {
+	hdr, err := readHeader(hdu.f, int(hdu.id)-1)
+	if err != nil {
+		return err
+	}
+	hdu.header = hdr
+	return nil
+}
+
 func newTable(f *File, hdr Header, i int) (hdu HDU, err error) {
 	c_status := C.int(0)
 	c_id := C.int(0)
@@ -355,7 +1043,8 @@ func newTable(f *File, hdr Header, i int) (hdu HDU, err error) {
 
 		card = get("TNULL", ii)
 		if card != nil {
-			col.Null = card.Value.(string)
+			// an integer for binary tables, a string for ASCII tables.
+			col.Null = fmt.Sprint(card.Value)
 		}
 
 		card = get("TSCAL", ii)
@@ -439,11 +1128,23 @@ func newTable(f *File, hdr Header, i int) (hdu HDU, err error) {
 			}
 			col.Type = TypeCode(c_type)
 			col.Len = int(c_repeat)
-			col.Value = govalue_from_typecode(col.Type, col.Len)
+			switch col.Type {
+			case TSTRING:
+				col.width = int(c_width)
+				_, n, _ := col.strShape()
+				col.Value = govalue_from_typecode(col.Type, n)
+			default:
+				col.Value = govalue_from_typecode(col.Type, col.Len)
+				if len(col.Dim) >= 2 && col.Type > 0 {
+					if v, err := col.Reshape(col.Value); err == nil {
+						col.Value = v
+					}
+				}
+			}
 		}
 	}
 
-	hdu = &Table{
+	table := &Table{
 		f:       f,
 		id:      c_id,
 		header:  hdr,
@@ -452,11 +1153,22 @@ func newTable(f *File, hdr Header, i int) (hdu HDU, err error) {
 		col2idx: col2idx,
 		data:    nil,
 	}
-	return hdu, err
+	table.attachCols()
+	return table, err
+}
+
+// attachCols makes the columns of this table refer back to it.
+func (hdu *Table) attachCols() {
+	for i := range hdu.cols {
+		hdu.cols[i].table = hdu
+		hdu.cols[i].index = i
+	}
 }
 
-// NewTable creates a new table in the given FITS file
-func NewTable(f *File, name string, cols []Column, hdutype HDUType) (*Table, error) {
+// NewTable creates a new table in the given FITS file.
+// cards are additional header records (e.g. COMMENT or HISTORY Cards),
+// written after the columns definition.
+func NewTable(f *File, name string, cols []Column, hdutype HDUType, cards ...Card) (*Table, error) {
 	var err error
 	var table *Table
 	mode, err := f.Mode()
@@ -467,12 +1179,73 @@ func NewTable(f *File, name string, cols []Column, hdutype HDUType) (*Table, err
 		return table, READONLY_FILE
 	}
 
-	nhdus := len(f.hdus)
-
 	if len(cols) <= 0 {
 		return table, fmt.Errorf("cfitsio.NewTable: invalid number of columns (%v)", len(cols))
 	}
 
+	err = createTable(f, name, cols, hdutype, false)
+	if err != nil {
+		return table, err
+	}
+
+	err = writeCards(f, cards)
+	if err != nil {
+		return table, err
+	}
+
+	// CFITSIO inserts a dummy primary HDU before the table if the file was empty:
+	// register all the HDUs up to (and including) the new table.
+	last := f.HDUNum()
+	for i := len(f.hdus); i <= last; i++ {
+		hdu, err := f.readHDU(i)
+		if err != nil {
+			return table, err
+		}
+		f.hdus = append(f.hdus, hdu)
+	}
+
+	table, ok := f.hdus[len(f.hdus)-1].(*Table)
+	if !ok {
+		return nil, fmt.Errorf("cfitsio: last HDU is not a table (%T)", f.hdus[len(f.hdus)-1])
+	}
+	return table, err
+}
+
+// InsertTable inserts a new table in the given FITS file, before the i-th HDU (index: 0-based!).
+// The following HDUs are renumbered.
+// cards are additional header records, written after the columns definition.
+func InsertTable(f *File, i int, name string, cols []Column, hdutype HDUType, cards ...Card) (*Table, error) {
+	err := f.insertHDU(i)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cols) <= 0 {
+		return nil, fmt.Errorf("cfitsio.InsertTable: invalid number of columns (%v)", len(cols))
+	}
+
+	err = createTable(f, name, cols, hdutype, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writeCards(f, cards)
+	if err != nil {
+		return nil, err
+	}
+
+	hdu, err := f.registerHDU(i)
+	if err != nil {
+		return nil, err
+	}
+	return hdu.(*Table), nil
+}
+
+// createTable creates a new table with columns cols in file f.
+// If insert is true, the table is inserted right after the current HDU.
+// Otherwise, it is appended at the end of the file.
+func createTable(f *File, name string, cols []Column, hdutype HDUType, insert bool) error {
+	var err error
 	c_status := C.int(0)
 	c_sz := C.int(len(cols))
 	c_types := C.char_array_new(c_sz)
@@ -493,8 +1266,9 @@ func NewTable(f *File, name string, cols []Column, hdutype HDUType) (*Table, err
 
 		err = col.inferFormat(hdutype)
 		if err != nil {
-			return table, err
+			return err
 		}
+		col.inferDim()
 		c_form := C.CString(col.Format)
 		defer C.free(unsafe.Pointer(c_form))
 		C.char_array_set(c_forms, c_idx, c_form)
@@ -504,19 +1278,64 @@ func NewTable(f *File, name string, cols []Column, hdutype HDUType) (*Table, err
 		C.char_array_set(c_units, c_idx, c_unit)
 	}
 
-	C.fits_create_tbl(f.c, C.int(hdutype), 0, C.int(len(cols)), c_types, c_forms, c_units, c_hduname, &c_status)
+	switch {
+	case !insert:
+		C.fits_create_tbl(f.c, C.int(hdutype), 0, C.int(len(cols)), c_types, c_forms, c_units, c_hduname, &c_status)
+	case hdutype == ASCII_TBL:
+		C.fits_insert_atbl(f.c, 0, 0, C.int(len(cols)), c_types, nil, c_forms, c_units, c_hduname, &c_status)
+	default:
+		C.fits_insert_btbl(f.c, 0, C.int(len(cols)), c_types, c_forms, c_units, c_hduname, 0, &c_status)
+	}
 	if c_status > 0 {
-		return table, to_err(c_status)
+		return to_err(c_status)
 	}
 
-	hdu, err := f.readHDU(nhdus)
+	for i := range cols {
+		err = writeColumnKeys(f, i, &cols[i], hdutype)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeColumnKeys writes the TDIM, TSCAL, TZERO, TDISP and TNULL keywords of col,
+// the column icol (0-based) of the current HDU, of type hdutype.
+// Keywords left to their default value are not written.
+func writeColumnKeys(f *File, icol int, col *Column, hdutype HDUType) error {
+	err := writeTDim(f, icol, col.Dim)
 	if err != nil {
-		return table, err
+		return err
 	}
-	f.hdus = append(f.hdus, hdu)
-	table = hdu.(*Table)
 
-	return table, err
+	var cards []Card
+	if (col.Bscale != 0 && col.Bscale != 1) || col.Bzero != 0 {
+		scale := col.Bscale
+		if scale == 0 {
+			scale = 1
+		}
+		cards = append(cards,
+			Card{Name: fmt.Sprintf("TSCAL%d", icol+1), Value: scale, Comment: "data scaling factor"},
+			Card{Name: fmt.Sprintf("TZERO%d", icol+1), Value: col.Bzero, Comment: "data offset"},
+		)
+	}
+	if col.Display != "" {
+		cards = append(cards, Card{Name: fmt.Sprintf("TDISP%d", icol+1), Value: col.Display, Comment: "display format"})
+	}
+	if col.Null != "" {
+		// the null value of a binary table column is an integer,
+		// the one of an ASCII table column a string.
+		var null interface{} = col.Null
+		if hdutype == BINARY_TBL {
+			v, err := strconv.ParseInt(strings.TrimSpace(col.Null), 10, 64)
+			if err != nil {
+				return fmt.Errorf("cfitsio: invalid null value %q for column %q", col.Null, col.Name)
+			}
+			null = v
+		}
+		cards = append(cards, Card{Name: fmt.Sprintf("TNULL%d", icol+1), Value: null, Comment: "undefined value"})
+	}
+	return writeCards(f, cards)
 }
 
 // NewTableFrom creates a new table in the given FITS file, using the struct v as schema
@@ -529,19 +1348,42 @@ func NewTableFrom(f *File, name string, v Value, hdutype HDUType) (*Table, error
 	cols := make([]Column, rt.NumField())
 	for i := range cols {
 		ft := rt.Field(i)
-		name := ft.Tag.Get("fits")
-		if name == "" {
-			name = ft.Name
-		}
+		cname, opts := fieldTag(ft)
 		n := 0
 		if ft.Type.Kind() == reflect.Array {
 			n = ft.Type.Len()
 		}
 		cols[i] = Column{
-			Name:  name,
+			Name:  cname,
 			Len:   n,
 			Value: rv.Field(i).Interface(),
 		}
+		if v, ok := opts["width"]; ok {
+			width, err := strconv.Atoi(v)
+			if err != nil || width <= 0 {
+				return nil, fmt.Errorf("cfitsio: invalid string width %q for field %q", v, ft.Name)
+			}
+			format, err := stringFormat(ft.Type, width, hdutype)
+			if err != nil {
+				return nil, err
+			}
+			cols[i].Format = format
+		}
+		if v, ok := opts["bits"]; ok {
+			nbits := 0
+			if v != "" {
+				var err error
+				nbits, err = strconv.Atoi(v)
+				if err != nil || nbits <= 0 {
+					return nil, fmt.Errorf("cfitsio: invalid number of bits %q for field %q", v, ft.Name)
+				}
+			}
+			format, err := bitsFormat(ft.Type, nbits)
+			if err != nil {
+				return nil, err
+			}
+			cols[i].Format = format
+		}
 	}
 	return NewTable(f, name, cols, hdutype)
 }
@@ -549,23 +1391,14 @@ func NewTableFrom(f *File, name string, v Value, hdutype HDUType) (*Table, error
 // Write writes a row to the table
 func (hdu *Table) Write(args ...interface{}) error {
 
-	err := hdu.seekHDU()
+	err := hdu.seekWriteColumns()
 	if err != nil {
 		return err
 	}
 
 	irow := hdu.NumRows()
 
-	defer func() {
-		// update nrows
-		c_nrows := C.long(0)
-		c_status := C.int(0)
-		C.fits_get_num_rows(hdu.f.c, &c_nrows, &c_status)
-		if c_status > 0 {
-			return
-		}
-		hdu.nrows = int64(c_nrows)
-	}()
+	defer hdu.updateNumRows()
 
 	switch len(args) {
 	case 0:
@@ -584,6 +1417,17 @@ func (hdu *Table) Write(args ...interface{}) error {
 	return hdu.write(irow, args...)
 }
 
+// updateNumRows updates the cached number of rows from the current HDU.
+func (hdu *Table) updateNumRows() {
+	c_nrows := C.long(0)
+	c_status := C.int(0)
+	C.fits_get_num_rows(hdu.f.c, &c_nrows, &c_status)
+	if c_status > 0 {
+		return
+	}
+	hdu.nrows = int64(c_nrows)
+}
+
 func (hdu *Table) writeMap(irow int64, data map[string]interface{}) error {
 	var err error
 
@@ -609,11 +1453,7 @@ func (hdu *Table) writeStruct(irow int64, data interface{}) error {
 	rv := reflect.ValueOf(data).Elem()
 	icols := make([][2]int, 0, rt.NumField())
 	for i := 0; i < rt.NumField(); i++ {
-		f := rt.Field(i)
-		n := f.Tag.Get("fits")
-		if n == "" {
-			n = f.Name
-		}
+		n, _ := fieldTag(rt.Field(i))
 		icol := hdu.Index(n)
 		if icol >= 0 {
 			icols = append(icols, [2]int{i, icol})
@@ -679,7 +1519,7 @@ func CopyTableRange(dst, src *Table, beg, end int64) error {
 		dst.nrows = int64(c_nrows)
 	}()
 
-	err = dst.seekHDU()
+	err = dst.seekWrite()
 	if err != nil {
 		return err
 	}
@@ -715,7 +1555,7 @@ func init() {
 	g_hdus[ASCII_TBL] = newTable
 	g_hdus[BINARY_TBL] = newTable
 	g_cfits2go = map[TypeCode]reflect.Type{
-		TBIT:        reflect.TypeOf((*byte)(nil)).Elem(),
+		TBIT:        reflect.TypeOf((*bool)(nil)).Elem(),
 		TBYTE:       reflect.TypeOf((*byte)(nil)).Elem(),
 		TSBYTE:      reflect.TypeOf((*int8)(nil)).Elem(),
 		TLOGICAL:    reflect.TypeOf((*bool)(nil)).Elem(),
@@ -752,6 +1592,10 @@ func init() {
 
 	g_go2cfits = make(map[reflect.Type]TypeCode, len(g_cfits2go))
 	for k, v := range g_cfits2go {
+		if k == TBIT {
+			// bool values are stored as logicals by default.
+			continue
+		}
 		g_go2cfits[v] = k
 	}
 }
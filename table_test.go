@@ -2070,4 +2070,910 @@ func TestTableMapsRW(t *testing.T) {
 	}
 }
 
+func TestTableColumnRW(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	const nrows = 10
+	xs := make([]float64, nrows)
+	ids := make([]int32, nrows)
+	vs := make([]float32, 3*nrows)
+	for i := 0; i < nrows; i++ {
+		xs[i] = float64(i) + 0.5
+		ids[i] = int32(i)
+		for j := 0; j < 3; j++ {
+			vs[3*i+j] = float32(10*i + j)
+		}
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTable(
+				&f, "columns",
+				[]Column{
+					{Name: "x", Value: float64(0)},
+					{Name: "id", Value: int32(0)},
+					{Name: "v", Format: "3E"},
+				},
+				BINARY_TBL,
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			// append in two chunks
+			for _, rng := range [][2]int{{0, 4}, {4, nrows}} {
+				beg := tbl.NumRows()
+				x := xs[rng[0]:rng[1]]
+				err = tbl.WriteColumn(0, beg, &x)
+				if err != nil {
+					t.Fatalf("error writing column x: %v", err)
+				}
+				id := ids[rng[0]:rng[1]]
+				err = tbl.WriteColumn(1, beg, &id)
+				if err != nil {
+					t.Fatalf("error writing column id: %v", err)
+				}
+				v := vs[3*rng[0] : 3*rng[1]]
+				err = tbl.WriteColumn(2, beg, &v)
+				if err != nil {
+					t.Fatalf("error writing column v: %v", err)
+				}
+				if tbl.NumRows() != int64(rng[1]) {
+					t.Fatalf("expected %d rows. got %d", rng[1], tbl.NumRows())
+				}
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+
+			var x []float64
+			err = tbl.ReadColumn(0, 0, tbl.NumRows(), &x)
+			if err != nil {
+				t.Fatalf("error reading column x: %v", err)
+			}
+			if !reflect.DeepEqual(x, xs) {
+				t.Fatalf("column x: expected %v. got %v", xs, x)
+			}
+
+			// read into a larger slice, over a sub-range
+			id := make([]int32, 100)
+			err = tbl.ReadColumn(1, 2, 5, &id)
+			if err != nil {
+				t.Fatalf("error reading column id: %v", err)
+			}
+			if !reflect.DeepEqual(id, ids[2:5]) {
+				t.Fatalf("column id: expected %v. got %v", ids[2:5], id)
+			}
+
+			// read ids as float64, past the end of the table
+			var fid []float64
+			err = tbl.ReadColumn(1, 8, 20, &fid)
+			if err != nil {
+				t.Fatalf("error reading column id: %v", err)
+			}
+			if !reflect.DeepEqual(fid, []float64{8, 9}) {
+				t.Fatalf("column id: expected %v. got %v", []float64{8, 9}, fid)
+			}
+
+			var v []float32
+			err = tbl.ReadColumn(2, 0, tbl.NumRows(), &v)
+			if err != nil {
+				t.Fatalf("error reading column v: %v", err)
+			}
+			if !reflect.DeepEqual(v, vs) {
+				t.Fatalf("column v: expected %v. got %v", vs, v)
+			}
+
+			var s []string
+			err = tbl.ReadColumn(0, 0, tbl.NumRows(), &s)
+			if err == nil {
+				t.Fatalf("expected an error reading a column into a []string")
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestNewTableWithoutPrimary(t *testing.T) {
+	f, err := CreateInMemory()
+	if err != nil {
+		t.Fatalf("error creating new file: %v", err)
+	}
+	defer f.Close()
+
+	// CFITSIO inserts a dummy primary HDU before the table.
+	tbl, err := NewTable(&f, "data", []Column{{Name: "x", Value: float64(0)}}, BINARY_TBL)
+	if err != nil {
+		t.Fatalf("error creating table: %v", err)
+	}
+	defer tbl.Close()
+
+	if len(f.HDUs()) != 2 {
+		t.Fatalf("expected 2 HDUs. got %d", len(f.HDUs()))
+	}
+	if _, ok := f.HDU(0).(*PrimaryHDU); !ok {
+		t.Fatalf("expected a primary HDU. got %T", f.HDU(0))
+	}
+	if f.HDU(1) != HDU(tbl) {
+		t.Fatalf("expected the table as second HDU. got %T", f.HDU(1))
+	}
+}
+
+func TestTableColumnSchema(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	colnames := func(tbl *Table) []string {
+		var names []string
+		for _, col := range tbl.Cols() {
+			names = append(names, col.Name)
+		}
+		return names
+	}
+
+	xs := []float64{1, 2, 3}
+	flags := []float64{10, 10.5, 11}
+	want := []string{"flag", "energy", "v", "y"}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTable(
+				&f, "schema",
+				[]Column{
+					{Name: "x", Value: float64(0)},
+					{Name: "id", Value: int32(0)},
+					{Name: "v", Format: "2E"},
+				},
+				BINARY_TBL,
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			err = tbl.WriteColumn(0, 0, &xs)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+
+			err = tbl.AppendColumn(Column{Name: "y", Value: float64(0), Unit: "cm"})
+			if err != nil {
+				t.Fatalf("error appending column: %v", err)
+			}
+			err = tbl.InsertColumn(0, Column{
+				Name: "flag", Value: int16(0),
+				Bscale: 0.5, Bzero: 10, Display: "F6.1", Null: "-99",
+			})
+			if err != nil {
+				t.Fatalf("error inserting column: %v", err)
+			}
+			err = tbl.WriteColumn(0, 0, &flags)
+			if err != nil {
+				t.Fatalf("error writing scaled column: %v", err)
+			}
+			err = tbl.RenameColumn(1, "energy")
+			if err != nil {
+				t.Fatalf("error renaming column: %v", err)
+			}
+			err = tbl.DeleteColumn(tbl.Index("id"))
+			if err != nil {
+				t.Fatalf("error deleting column: %v", err)
+			}
+			err = tbl.ModifyColumnFormat(tbl.Index("v"), "4E")
+			if err != nil {
+				t.Fatalf("error modifying column format: %v", err)
+			}
+			err = tbl.ModifyColumnFormat(tbl.Index("v"), "4D")
+			if err == nil {
+				t.Fatalf("expected an error changing the data type of a column")
+			}
+			err = tbl.DeleteColumn(10)
+			if err == nil {
+				t.Fatalf("expected an error deleting an invalid column")
+			}
+
+			if !reflect.DeepEqual(colnames(tbl), want) {
+				t.Fatalf("expected columns %v. got %v", want, colnames(tbl))
+			}
+			if tbl.Index("energy") != 1 || tbl.Index("x") != -1 {
+				t.Fatalf("invalid column indices: energy=%d, x=%d", tbl.Index("energy"), tbl.Index("x"))
+			}
+			if tbl.Col(tbl.Index("y")).Unit != "cm" {
+				t.Fatalf("expected unit %q. got %q", "cm", tbl.Col(tbl.Index("y")).Unit)
+			}
+			if tbl.Col(tbl.Index("v")).Len != 4 {
+				t.Fatalf("expected vector length 4. got %d", tbl.Col(tbl.Index("v")).Len)
+			}
+			hdr := tbl.Header()
+			if card := hdr.Get("TTYPE2"); card == nil || card.Value != "energy" {
+				t.Fatalf("expected TTYPE2=%q. got %v", "energy", card)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			if !reflect.DeepEqual(colnames(tbl), want) {
+				t.Fatalf("expected columns %v. got %v", want, colnames(tbl))
+			}
+
+			var energy []float64
+			err = tbl.ReadColumn(tbl.Index("energy"), 0, tbl.NumRows(), &energy)
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			if !reflect.DeepEqual(energy, xs) {
+				t.Fatalf("expected column %v. got %v", xs, energy)
+			}
+
+			flag := tbl.Col(0)
+			if flag.Bscale != 0.5 || flag.Bzero != 10 {
+				t.Fatalf("unexpected scaling: TSCAL=%v TZERO=%v", flag.Bscale, flag.Bzero)
+			}
+			if flag.Display != "F6.1" || flag.Null != "-99" {
+				t.Fatalf("unexpected TDISP=%q TNULL=%q", flag.Display, flag.Null)
+			}
+			var vs []float64
+			err = tbl.ReadColumn(0, 0, tbl.NumRows(), &vs)
+			if err != nil {
+				t.Fatalf("error reading scaled column: %v", err)
+			}
+			if !reflect.DeepEqual(vs, flags) {
+				t.Fatalf("expected column %v. got %v", flags, vs)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestTableInsertDeleteRows(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	readIDs := func(tbl *Table) []int32 {
+		var ids []int32
+		err := tbl.ReadColumn(0, 0, tbl.NumRows(), &ids)
+		if err != nil {
+			t.Fatalf("error reading column: %v", err)
+		}
+		return ids
+	}
+
+	want := []int32{2, 3, 4, 5, 6}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTable(&f, "rows", []Column{{Name: "id", Value: int32(0)}}, BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			ids := []int32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+			err = tbl.WriteColumn(0, 0, &ids)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+
+			err = tbl.InsertRows(0, 2)
+			if err != nil {
+				t.Fatalf("error inserting rows: %v", err)
+			}
+			if tbl.NumRows() != 12 {
+				t.Fatalf("expected 12 rows. got %d", tbl.NumRows())
+			}
+			head := []int32{-2, -1}
+			err = tbl.WriteColumn(0, 0, &head)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+			got := readIDs(tbl)
+			if !reflect.DeepEqual(got[:3], []int32{-2, -1, 0}) {
+				t.Fatalf("unexpected ids after insertion: %v", got)
+			}
+
+			rows, err := tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			defer rows.Close()
+
+			err = tbl.DeleteRows(0, 2)
+			if err != nil {
+				t.Fatalf("error deleting rows: %v", err)
+			}
+			err = tbl.DeleteRowList([]int64{9, 0})
+			if err != nil {
+				t.Fatalf("error deleting row list: %v", err)
+			}
+			err = tbl.DeleteRowRange("1")
+			if err != nil {
+				t.Fatalf("error deleting row range: %v", err)
+			}
+			n, err := tbl.DeleteRowsWhere("id > 6")
+			if err != nil {
+				t.Fatalf("error deleting rows: %v", err)
+			}
+			if n != 2 {
+				t.Fatalf("expected 2 deleted rows. got %d", n)
+			}
+			err = tbl.DeleteRows(4, 10)
+			if err == nil {
+				t.Fatalf("expected an error deleting rows past the end of the table")
+			}
+
+			if tbl.NumRows() != int64(len(want)) {
+				t.Fatalf("expected %d rows. got %d", len(want), tbl.NumRows())
+			}
+			if got := readIDs(tbl); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+
+			// the iterator created before the deletions stops at the new end.
+			count := 0
+			for rows.Next() {
+				var id int32
+				err = rows.Scan(&id)
+				if err != nil {
+					t.Fatalf("error scanning row: %v", err)
+				}
+				count++
+			}
+			if count != len(want) {
+				t.Fatalf("expected %d rows from iterator. got %d", len(want), count)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			if got := readIDs(tbl); !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected ids %v. got %v", want, got)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestTableSelect(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	cols := []Column{
+		{Name: "id", Value: int32(0)},
+		{Name: "energy", Value: float64(0)},
+	}
+	ids := []int32{0, 1, 2, 3, 4, 5}
+	energies := []float64{10, 200, 30, 400, 500, 60}
+
+	f, err := Create("new.fits")
+	if err != nil {
+		t.Fatalf("error creating new file: %v", err)
+	}
+	defer f.Close()
+
+	phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+	if err != nil {
+		t.Fatalf("error creating PHDU: %v", err)
+	}
+	defer phdu.Close()
+
+	tbl, err := NewTable(&f, "events", cols, BINARY_TBL)
+	if err != nil {
+		t.Fatalf("error creating table: %v", err)
+	}
+	defer tbl.Close()
+
+	err = tbl.WriteColumn(0, 0, &ids)
+	if err != nil {
+		t.Fatalf("error writing column: %v", err)
+	}
+	err = tbl.WriteColumn(1, 0, &energies)
+	if err != nil {
+		t.Fatalf("error writing column: %v", err)
+	}
+
+	rows, err := tbl.Select("energy > 100")
+	if err != nil {
+		t.Fatalf("error selecting rows: %v", err)
+	}
+	var got []int32
+	for rows.Next() {
+		var id int32
+		var energy float64
+		err = rows.Scan(&id, &energy)
+		if err != nil {
+			t.Fatalf("error scanning row: %v", err)
+		}
+		got = append(got, id)
+	}
+	err = rows.Err()
+	if err != nil {
+		t.Fatalf("rows.Err: %v", err)
+	}
+	want := []int32{1, 3, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected ids %v. got %v", want, got)
+	}
+
+	_, err = tbl.Select("energy >")
+	if err != PARSE_SYNTAX_ERR {
+		t.Fatalf("expected error %v. got %v", PARSE_SYNTAX_ERR, err)
+	}
+
+	out, err := Create("out.fits")
+	if err != nil {
+		t.Fatalf("error creating new file: %v", err)
+	}
+	defer out.Close()
+
+	ophdu, err := NewPrimaryHDU(&out, NewDefaultHeader())
+	if err != nil {
+		t.Fatalf("error creating PHDU: %v", err)
+	}
+	defer ophdu.Close()
+
+	dst, err := NewTable(&out, "selected", cols, BINARY_TBL)
+	if err != nil {
+		t.Fatalf("error creating table: %v", err)
+	}
+	defer dst.Close()
+
+	err = tbl.CopyWhere(dst, "id % 2 == 0")
+	if err != nil {
+		t.Fatalf("error copying rows: %v", err)
+	}
+	if dst.NumRows() != 3 {
+		t.Fatalf("expected 3 rows. got %d", dst.NumRows())
+	}
+	var dids []int32
+	err = dst.ReadColumn(0, 0, dst.NumRows(), &dids)
+	if err != nil {
+		t.Fatalf("error reading column: %v", err)
+	}
+	if !reflect.DeepEqual(dids, []int32{0, 2, 4}) {
+		t.Fatalf("expected ids %v. got %v", []int32{0, 2, 4}, dids)
+	}
+
+	// same row width, different columns.
+	bad, err := NewTable(
+		&out, "bad",
+		[]Column{{Name: "energy", Value: float64(0)}, {Name: "id", Value: int32(0)}},
+		BINARY_TBL,
+	)
+	if err != nil {
+		t.Fatalf("error creating table: %v", err)
+	}
+	defer bad.Close()
+
+	err = tbl.CopyWhere(bad, "id % 2 == 0")
+	if err == nil {
+		t.Fatalf("expected an error copying rows into a table with different columns")
+	}
+	if bad.NumRows() != 0 {
+		t.Fatalf("expected 0 rows. got %d", bad.NumRows())
+	}
+}
+
+func TestTableCalculate(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	xs := []float64{3, 6, 0, 5}
+	ys := []float64{4, 8, 1, 12}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTable(
+				&f, "calc",
+				[]Column{{Name: "X", Value: float64(0)}, {Name: "Y", Value: float64(0)}},
+				BINARY_TBL,
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			err = tbl.WriteColumn(0, 0, &xs)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+			err = tbl.WriteColumn(1, 0, &ys)
+			if err != nil {
+				t.Fatalf("error writing column: %v", err)
+			}
+
+			err = tbl.Calculate("sqrt(X**2+Y**2)", "R", "1D")
+			if err != nil {
+				t.Fatalf("error calculating column: %v", err)
+			}
+			if tbl.Index("R") != 2 {
+				t.Fatalf("expected column R at index 2. got %d", tbl.Index("R"))
+			}
+
+			// overwrite an existing column
+			err = tbl.Calculate("X*10", "X", "")
+			if err != nil {
+				t.Fatalf("error calculating column: %v", err)
+			}
+			if tbl.NumCols() != 3 {
+				t.Fatalf("expected 3 columns. got %d", tbl.NumCols())
+			}
+
+			err = tbl.Calculate("X +", "Z", "1D")
+			if err != PARSE_SYNTAX_ERR {
+				t.Fatalf("expected error %v. got %v", PARSE_SYNTAX_ERR, err)
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			var r []float64
+			err = tbl.ReadColumn(tbl.Index("R"), 0, tbl.NumRows(), &r)
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			if !reflect.DeepEqual(r, []float64{5, 10, 1, 13}) {
+				t.Fatalf("expected R=%v. got %v", []float64{5, 10, 1, 13}, r)
+			}
+
+			var x []float64
+			err = tbl.ReadColumn(tbl.Index("X"), 0, tbl.NumRows(), &x)
+			if err != nil {
+				t.Fatalf("error reading column: %v", err)
+			}
+			if !reflect.DeepEqual(x, []float64{30, 60, 0, 50}) {
+				t.Fatalf("expected X=%v. got %v", []float64{30, 60, 0, 50}, x)
+			}
+
+			var v []float64
+			err = tbl.Eval("R - Y", 1, 3, &v)
+			if err != nil {
+				t.Fatalf("error evaluating expression: %v", err)
+			}
+			if !reflect.DeepEqual(v, []float64{2, 0}) {
+				t.Fatalf("expected %v. got %v", []float64{2, 0}, v)
+			}
+
+			var flags []bool
+			err = tbl.Eval("Y > 5", 0, tbl.NumRows(), &flags)
+			if err != nil {
+				t.Fatalf("error evaluating expression: %v", err)
+			}
+			if !reflect.DeepEqual(flags, []bool{false, true, false, true}) {
+				t.Fatalf("expected %v. got %v", []bool{false, true, false, true}, flags)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestTableVLA(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	type Event struct {
+		Wave  []float32 `fits:"wave"`
+		Flags []bool    `fits:"flags"`
+		Label []string  `fits:"label"`
+		Hits  []uint16  `fits:"hits"`
+	}
+	events := []Event{
+		{Wave: []float32{1, 2, 3}, Flags: []bool{true, false}, Label: []string{"first"}, Hits: []uint16{1}},
+		{Wave: []float32{}, Flags: []bool{}, Label: []string{}, Hits: []uint16{}},
+		{Wave: []float32{4, 5, 6, 7, 8}, Flags: []bool{false, true, true}, Label: []string{"third event"}, Hits: []uint16{2, 3}},
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTable(
+				&f, "events",
+				[]Column{
+					{Name: "wave", Value: []float32{}},
+					{Name: "flags", Value: []bool{}},
+					{Name: "label", Value: []string{}},
+					{Name: "hits", Value: []uint16{}},
+				},
+				BINARY_TBL,
+			)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			if tbl.Col(2).Format != "QA" {
+				t.Fatalf("expected format %q. got %q", "QA", tbl.Col(2).Format)
+			}
+
+			for i := range events {
+				err = tbl.Write(&events[i])
+				if err != nil {
+					t.Fatalf("error writing row [%v]: %v", i, err)
+				}
+			}
+			if tbl.NumRows() != int64(len(events)) {
+				t.Fatalf("expected %d rows. got %d", len(events), tbl.NumRows())
+			}
+
+			err = tbl.Col(2).write(tbl.f, 2, 0, []string{"a", "b"})
+			if err == nil {
+				t.Fatalf("expected an error writing many strings in a row")
+			}
+
+			descs, err := tbl.Col(0).Descriptors()
+			if err != nil {
+				t.Fatalf("error reading descriptors: %v", err)
+			}
+			if len(descs) != len(events) {
+				t.Fatalf("expected %d descriptors. got %d", len(events), len(descs))
+			}
+			for i, desc := range descs {
+				if desc.Len != int64(len(events[i].Wave)) {
+					t.Fatalf("row %d: expected %d elements. got %d", i, len(events[i].Wave), desc.Len)
+				}
+			}
+
+			// rewriting a row leaves its former array unused in the heap.
+			events[0].Wave = []float32{9, 10}
+			err = tbl.Col(0).write(tbl.f, 0, 0, events[0].Wave)
+			if err != nil {
+				t.Fatalf("error rewriting row: %v", err)
+			}
+			err = tbl.reloadHeader()
+			if err != nil {
+				t.Fatalf("error reading header: %v", err)
+			}
+			hdr := tbl.Header()
+			pcount := hdr.Get("PCOUNT").Value.(int64)
+
+			err = tbl.CompressHeap()
+			if err != nil {
+				t.Fatalf("error compressing heap: %v", err)
+			}
+			hdr = tbl.Header()
+			if v := hdr.Get("PCOUNT").Value.(int64); v >= pcount {
+				t.Fatalf("expected heap to shrink from %d bytes. got %d", pcount, v)
+			}
+
+			_, err = tbl.Col(0).Descriptors()
+			if err != nil {
+				t.Fatalf("error reading descriptors: %v", err)
+			}
+			var col Column
+			_, err = col.Descriptors()
+			if err == nil {
+				t.Fatalf("expected an error reading descriptors of a detached column")
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			rows, err := tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			defer rows.Close()
+
+			i := 0
+			for rows.Next() {
+				var evt Event
+				err = rows.Scan(&evt)
+				if err != nil {
+					t.Fatalf("error scanning row [%v]: %v", i, err)
+				}
+				if !reflect.DeepEqual(evt, events[i]) {
+					t.Fatalf("row %d:\nexpected=%v\ngot=%v", i, events[i], evt)
+				}
+				i++
+			}
+			if i != len(events) {
+				t.Fatalf("expected %d rows. got %d", len(events), i)
+			}
+
+			descs, err := tbl.Col(3).Descriptors()
+			if err != nil {
+				t.Fatalf("error reading descriptors: %v", err)
+			}
+			if descs[1].Len != 0 || descs[2].Len != 2 {
+				t.Fatalf("unexpected descriptors %v", descs)
+			}
+			_, err = tbl.Col(0).Descriptors()
+			if err != nil {
+				t.Fatalf("error reading descriptors: %v", err)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
 // EOF
@@ -0,0 +1,151 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isNestedArray returns whether rt is a multi-dimensional array type, e.g. [N][M]T.
+func isNestedArray(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Array && rt.Elem().Kind() == reflect.Array
+}
+
+// arrayElem returns the element type of the (possibly multi-dimensional) array type rt
+// and its total number of elements.
+func arrayElem(rt reflect.Type) (reflect.Type, int) {
+	n := 1
+	for rt.Kind() == reflect.Array {
+		n *= rt.Len()
+		rt = rt.Elem()
+	}
+	return rt, n
+}
+
+// arrayDims returns the TDIM dimensions of the multi-dimensional array type rt,
+// or nil if rt is not a multi-dimensional array.
+// FITS dimensions are in Fortran order: [N][M]T corresponds to TDIM=(M,N).
+func arrayDims(rt reflect.Type) []int64 {
+	if !isNestedArray(rt) {
+		return nil
+	}
+	var dims []int64
+	for rt.Kind() == reflect.Array {
+		dims = append([]int64{int64(rt.Len())}, dims...)
+		rt = rt.Elem()
+	}
+	return dims
+}
+
+// inferDim infers the TDIM dimensions of a Column from its Go value, if not already set.
+func (col *Column) inferDim() {
+	if col.Dim != nil || col.Value == nil {
+		return
+	}
+	col.Dim = arrayDims(reflect.TypeOf(col.Value))
+}
+
+// writeTDim writes the TDIM keyword of column icol (0-based) of the current HDU.
+func writeTDim(f *File, icol int, dims []int64) error {
+	if len(dims) == 0 {
+		return nil
+	}
+	c_dims := make([]C.LONGLONG, len(dims))
+	for i, dim := range dims {
+		c_dims[i] = C.LONGLONG(dim)
+	}
+	c_status := C.int(0)
+	C.fits_write_tdimll(f.c, C.int(icol+1), C.int(len(dims)), &c_dims[0], &c_status)
+	return to_err(c_status)
+}
+
+// readArray reads the value at column icol and row irow into ptr,
+// a pointer to a multi-dimensional array.
+// icol and irow are 0-based indices.
+func (col *Column) readArray(f *File, icol int, irow int64, ptr interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(ptr))
+	rt := reflect.TypeOf(rv.Interface())
+	elem, n := arrayElem(rt)
+	if n != col.Len {
+		return fmt.Errorf("cfitsio: column %q holds %d elements per row (not %d)", col.Name, col.Len, n)
+	}
+
+	flat := reflect.New(reflect.SliceOf(elem))
+	err := col.read(f, icol, irow, flat.Interface())
+	if err != nil {
+		return err
+	}
+
+	array := reflect.New(rt).Elem()
+	unflatten(array, flat.Elem(), 0)
+	rv.Set(array)
+	col.Value = rv.Interface()
+	return nil
+}
+
+// writeArray writes value, a multi-dimensional array, at column icol and row irow.
+// icol and irow are 0-based indices.
+func (col *Column) writeArray(f *File, icol int, irow int64, value interface{}) error {
+	rv := reflect.ValueOf(value)
+	elem, n := arrayElem(rv.Type())
+	flat := reflect.MakeSlice(reflect.SliceOf(elem), 0, n)
+	flat = flatten(flat, rv)
+	return col.write(f, icol, irow, flat.Interface())
+}
+
+// flatten appends the elements of the (possibly multi-dimensional) array v to the slice flat.
+func flatten(flat, v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Array {
+		return reflect.Append(flat, v)
+	}
+	for i := 0; i < v.Len(); i++ {
+		flat = flatten(flat, v.Index(i))
+	}
+	return flat
+}
+
+// unflatten fills the (possibly multi-dimensional) array v from the elements of flat,
+// starting at index i. It returns the index of the first unused element.
+func unflatten(v, flat reflect.Value, i int) int {
+	if v.Kind() != reflect.Array {
+		v.Set(flat.Index(i))
+		return i + 1
+	}
+	for j := 0; j < v.Len(); j++ {
+		i = unflatten(v.Index(j), flat, i)
+	}
+	return i
+}
+
+// Reshape returns a copy of flat, a slice or an array holding the elements of a cell of this Column,
+// shaped as a multi-dimensional array according to col.Dim.
+// For TDIM=(M,N) and elements of type T, Reshape returns a [N][M]T.
+func (col *Column) Reshape(flat interface{}) (interface{}, error) {
+	fv := reflect.ValueOf(flat)
+	if fv.Kind() != reflect.Slice && fv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cfitsio: Reshape needs a slice or an array. got %T", flat)
+	}
+
+	dims := col.Dim
+	if len(dims) == 0 {
+		dims = []int64{int64(fv.Len())}
+	}
+	rt := fv.Type().Elem()
+	n := 1
+	for _, dim := range dims {
+		n *= int(dim)
+		rt = reflect.ArrayOf(int(dim), rt)
+	}
+	if n != fv.Len() {
+		return nil, fmt.Errorf("cfitsio: can not reshape %d elements into %v", fv.Len(), dims)
+	}
+
+	array := reflect.New(rt).Elem()
+	unflatten(array, fv, 0)
+	return array.Interface(), nil
+}
+
+// EOF
@@ -0,0 +1,162 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTableTDim(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	type Frame struct {
+		ID    int32          `fits:"ID"`
+		Pix   [2][3]float32  `fits:"PIX"`
+		Cube  [2][2][2]int16 `fits:"CUBE"`
+		Flags [4]bool        `fits:"FLAGS"`
+	}
+	frames := []Frame{
+		{
+			ID:    1,
+			Pix:   [2][3]float32{{1, 2, 3}, {4, 5, 6}},
+			Cube:  [2][2][2]int16{{{1, 2}, {3, 4}}, {{5, 6}, {7, 8}}},
+			Flags: [4]bool{true, false, false, true},
+		},
+		{
+			ID:    2,
+			Pix:   [2][3]float32{{-1, -2, -3}, {-4, -5, -6}},
+			Cube:  [2][2][2]int16{{{-1, -2}, {-3, -4}}, {{-5, -6}, {-7, -8}}},
+			Flags: [4]bool{false, true, true, false},
+		},
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			tbl, err := NewTableFrom(&f, "frames", &frames[0], BINARY_TBL)
+			if err != nil {
+				t.Fatalf("error creating table: %v", err)
+			}
+			defer tbl.Close()
+
+			for i := range frames {
+				err = tbl.Write(&frames[i])
+				if err != nil {
+					t.Fatalf("error writing row [%v]: %v", i, err)
+				}
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			tbl := f.HDU(1).(*Table)
+			hdr := tbl.Header()
+			for _, ref := range []Card{
+				{Name: "TFORM2", Value: "6E"},
+				{Name: "TDIM2", Value: "(3,2)"},
+				{Name: "TFORM3", Value: "8I"},
+				{Name: "TDIM3", Value: "(2,2,2)"},
+			} {
+				card := hdr.Get(ref.Name)
+				if card == nil {
+					t.Fatalf("missing card %q", ref.Name)
+				}
+				if card.Value != ref.Value {
+					t.Fatalf("card %q. expected %v. got %v", ref.Name, ref.Value, card.Value)
+				}
+			}
+			if hdr.Get("TDIM4") != nil {
+				t.Fatalf("unexpected TDIM4 card for a 1-dimensional column")
+			}
+			if !reflect.DeepEqual(tbl.Col(1).Dim, []int64{3, 2}) {
+				t.Fatalf("expected dims [3 2]. got %v", tbl.Col(1).Dim)
+			}
+			if _, ok := tbl.Col(1).Value.([2][3]float32); !ok {
+				t.Fatalf("expected a [2][3]float32 value. got %T", tbl.Col(1).Value)
+			}
+
+			rows, err := tbl.Read(0, tbl.NumRows())
+			if err != nil {
+				t.Fatalf("error reading rows: %v", err)
+			}
+			defer rows.Close()
+
+			i := 0
+			for rows.Next() {
+				var frame Frame
+				err = rows.Scan(&frame)
+				if err != nil {
+					t.Fatalf("error scanning row [%v]: %v", i, err)
+				}
+				if !reflect.DeepEqual(frame, frames[i]) {
+					t.Fatalf("row %d:\nexpected=%v\ngot=%v", i, frames[i], frame)
+				}
+
+				var flat []int16
+				err = tbl.Col(2).read(tbl.f, 2, int64(i), &flat)
+				if err != nil {
+					t.Fatalf("error reading flat cell: %v", err)
+				}
+				cube, err := tbl.Col(2).Reshape(flat)
+				if err != nil {
+					t.Fatalf("error reshaping cell: %v", err)
+				}
+				if !reflect.DeepEqual(cube, frames[i].Cube) {
+					t.Fatalf("row %d: expected %v. got %v", i, frames[i].Cube, cube)
+				}
+				i++
+			}
+			if i != len(frames) {
+				t.Fatalf("expected %d rows. got %d", len(frames), i)
+			}
+
+			var bad [3][3]float32
+			err = tbl.Col(1).read(tbl.f, 1, 0, &bad)
+			if err == nil {
+				t.Fatalf("expected an error reading a cell into an array of the wrong size")
+			}
+			_, err = tbl.Col(1).Reshape([]float32{1, 2})
+			if err == nil {
+				t.Fatalf("expected an error reshaping a cell of the wrong size")
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+// EOF
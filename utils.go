@@ -34,6 +34,42 @@ func getLongValueString(f *File, key string) (string, error) {
 	return C.GoString(c_value), nil
 }
 
+// toFloat64 converts a numeric Card value into a float64.
+func toFloat64(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("cfitsio: non-numeric value %v (%T)", v, v)
+	}
+}
+
+// fieldTag parses the `fits:"NAME,opt1,opt2=value"` tag of a struct field.
+// It returns the column name (the field name if unset) and the options.
+func fieldTag(f reflect.StructField) (string, map[string]string) {
+	toks := strings.Split(f.Tag.Get("fits"), ",")
+	name := strings.TrimSpace(toks[0])
+	if name == "" {
+		name = f.Name
+	}
+	opts := make(map[string]string, len(toks)-1)
+	for _, tok := range toks[1:] {
+		kv := strings.SplitN(tok, "=", 2)
+		k := strings.TrimSpace(kv[0])
+		if k == "" {
+			continue
+		}
+		v := ""
+		if len(kv) == 2 {
+			v = strings.TrimSpace(kv[1])
+		}
+		opts[k] = v
+	}
+	return name, opts
+}
+
 // gotype2FITS returns the FITS format corresponding to a Go type
 // it returns "" if there is no corresponding FITS format.
 func gotype2FITS(v interface{}, hdu HDUType) string {
@@ -43,9 +79,31 @@ func gotype2FITS(v interface{}, hdu HDUType) string {
 	case reflect.Slice:
 		hdr = "Q"
 		rt = rt.Elem()
+		if rt.Kind() == reflect.String {
+			// variable-length character array: one string per row.
+			if hdu != BINARY_TBL {
+				return ""
+			}
+			return hdr + "A"
+		}
 	case reflect.Array:
-		hdr = fmt.Sprintf("%d", rt.Len())
+		elem, n := arrayElem(rt)
+		hdr = fmt.Sprintf("%d", n)
 		rt = rt.Elem()
+		if isNestedArray(reflect.TypeOf(v)) {
+			// multi-dimensional array: described by TDIM.
+			if elem.Kind() == reflect.String {
+				return ""
+			}
+			rt = elem
+		}
+		if rt.Kind() == reflect.String {
+			// vector of 80 characters strings.
+			if hdu != BINARY_TBL {
+				return ""
+			}
+			return fmt.Sprintf("%dA80", 80*reflect.TypeOf(v).Len())
+		}
 	default:
 		// no-op
 	}
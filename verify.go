@@ -0,0 +1,824 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Severity is the severity of an Issue found by Verify.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // the file departs from the recommendations of the FITS standard
+	SeverityError                   // the file violates the FITS standard
+)
+
+func (sev Severity) String() string {
+	switch sev {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		panic(fmt.Errorf("invalid Severity value (%v)", int(sev)))
+	}
+}
+
+// Issue is a conformance problem found by Verify.
+type Issue struct {
+	Severity Severity
+	HDU      int    // index (0-based) of the offending HDU, or -1 for problems with the whole file
+	Card     string // name of the offending Card, or "" if the problem is not tied to a Card
+	Msg      string
+}
+
+func (issue Issue) String() string {
+	if issue.Card == "" {
+		return fmt.Sprintf("hdu #%d: %v: %s", issue.HDU, issue.Severity, issue.Msg)
+	}
+	return fmt.Sprintf("hdu #%d: %v: %s: %s", issue.HDU, issue.Severity, issue.Card, issue.Msg)
+}
+
+// Report is the result of the verification of a FITS file.
+type Report struct {
+	NumHDUs int     // number of verified HDUs
+	Issues  []Issue // problems found, in HDU order
+}
+
+// OK returns whether no error was found. Warnings are allowed.
+func (r Report) OK() bool {
+	return r.NumErrors() == 0
+}
+
+// NumErrors returns the number of errors found.
+func (r Report) NumErrors() int {
+	return r.count(SeverityError)
+}
+
+// NumWarnings returns the number of warnings found.
+func (r Report) NumWarnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r Report) count(sev Severity) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+// HDU returns the problems found in the i-th HDU (0-based).
+func (r Report) HDU(i int) []Issue {
+	var issues []Issue
+	for _, issue := range r.Issues {
+		if issue.HDU == i {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Verify checks the conformance of all the HDUs of f to the FITS standard:
+// order and values of the mandatory keywords, value types of the reserved keywords,
+// consistency of the TFORMn, TDIMn and TBCOLn keywords of tables, header and data padding,
+// compatibility of BLANK with BITPIX and validity of the CHECKSUM and DATASUM keywords.
+func Verify(f *File) Report {
+	v := verifier{f: f, ihdu: -1}
+
+	ihdu := f.HDUNum()
+	defer f.SeekHDU(ihdu, 0)
+
+	// make sure pending changes are visible to the raw reads of checkPadding.
+	c_status := C.int(0)
+	C.fits_flush_file(f.c, &c_status)
+	if c_status > 0 {
+		v.errorf("", "could not flush file: %v", to_err(c_status))
+		return v.r
+	}
+
+	nhdus, err := f.NumHDUs()
+	if err != nil {
+		v.errorf("", "could not count HDUs: %v", err)
+		return v.r
+	}
+	v.r.NumHDUs = nhdus
+
+	for i := 0; i < nhdus; i++ {
+		v.verifyHDU(i)
+	}
+	return v.r
+}
+
+// rawKey is a header keyword, as written in the file.
+type rawKey struct {
+	name  string
+	value string // raw value, e.g. 'IMAGE   ' (with quotes) for a string
+}
+
+// readRawKeys reads all the keywords of the current HDU, in order.
+func readRawKeys(f *File) ([]rawKey, error) {
+	c_nexist := C.int(0)
+	c_nmore := C.int(0)
+	c_status := C.int(0)
+	C.fits_get_hdrspace(f.c, &c_nexist, &c_nmore, &c_status)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	c_key := C.CStringN(C.FLEN_KEYWORD)
+	defer C.free(unsafe.Pointer(c_key))
+	c_value := C.CStringN(C.FLEN_VALUE)
+	defer C.free(unsafe.Pointer(c_value))
+	c_com := C.CStringN(C.FLEN_COMMENT)
+	defer C.free(unsafe.Pointer(c_com))
+
+	keys := make([]rawKey, 0, int(c_nexist))
+	for i := 1; i <= int(c_nexist); i++ {
+		C.fits_read_keyn(f.c, C.int(i), c_key, c_value, c_com, &c_status)
+		if c_status > 0 {
+			return nil, to_err(c_status)
+		}
+		keys = append(keys, rawKey{
+			name:  C.GoString(c_key),
+			value: strings.TrimSpace(C.GoString(c_value)),
+		})
+	}
+	return keys, nil
+}
+
+// keyType returns the type of the raw value of a keyword:
+// 'L' (logical), 'I' (integer), 'F' (real), 'X' (complex) or 'C' (string).
+func keyType(value string) (byte, error) {
+	c_value := C.CString(value)
+	defer C.free(unsafe.Pointer(c_value))
+	var c_type C.char
+	c_status := C.int(0)
+	C.fits_get_keytype(c_value, &c_type, &c_status)
+	if c_status > 0 {
+		return 0, to_err(c_status)
+	}
+	return byte(c_type), nil
+}
+
+// g_reserved maps the reserved keywords of the FITS standard to the type of their value:
+// 'L' (logical), 'I' (integer), 'F' (real or integer) or 'C' (string).
+var g_reserved = map[string]byte{
+	"SIMPLE":   'L',
+	"EXTEND":   'L',
+	"GROUPS":   'L',
+	"XTENSION": 'C',
+	"BITPIX":   'I',
+	"NAXIS":    'I',
+	"PCOUNT":   'I',
+	"GCOUNT":   'I',
+	"TFIELDS":  'I',
+	"THEAP":    'I',
+	"EXTNAME":  'C',
+	"EXTVER":   'I',
+	"EXTLEVEL": 'I',
+	"BSCALE":   'F',
+	"BZERO":    'F',
+	"BUNIT":    'C',
+	"BLANK":    'I',
+	"DATAMIN":  'F',
+	"DATAMAX":  'F',
+	"DATE":     'C',
+	"DATE-OBS": 'C',
+	"ORIGIN":   'C',
+	"TELESCOP": 'C',
+	"INSTRUME": 'C',
+	"OBSERVER": 'C',
+	"OBJECT":   'C',
+	"AUTHOR":   'C',
+	"REFERENC": 'C',
+	"EQUINOX":  'F',
+	"EPOCH":    'F',
+	"CHECKSUM": 'C',
+	"DATASUM":  'C',
+}
+
+// g_reservedIndexed maps the indexed reserved keywords (without their index) to the type of their value.
+var g_reservedIndexed = map[string]byte{
+	"NAXIS": 'I',
+	"TTYPE": 'C',
+	"TFORM": 'C',
+	"TUNIT": 'C',
+	"TDISP": 'C',
+	"TDIM":  'C',
+	"TBCOL": 'I',
+	"TNULL": 'I',
+	"TSCAL": 'F',
+	"TZERO": 'F',
+	"CTYPE": 'C',
+	"CUNIT": 'C',
+	"CRPIX": 'F',
+	"CRVAL": 'F',
+	"CDELT": 'F',
+	"CROTA": 'F',
+}
+
+// g_columnKeys lists the indexed keywords describing table columns.
+var g_columnKeys = []string{"TTYPE", "TFORM", "TUNIT", "TDISP", "TDIM", "TBCOL", "TNULL", "TSCAL", "TZERO"}
+
+// splitIndex splits an indexed keyword name (e.g. "TFORM12") into its root and its index.
+// It returns an index of 0 if name is not indexed.
+func splitIndex(name string) (string, int) {
+	root := strings.TrimRight(name, "0123456789")
+	if root == name || root == "" {
+		return name, 0
+	}
+	i, err := strconv.Atoi(name[len(root):])
+	if err != nil {
+		return name, 0
+	}
+	return root, i
+}
+
+// verifier holds the state of Verify.
+type verifier struct {
+	f *File
+	r Report
+
+	// current HDU
+	ihdu     int
+	keys     []rawKey
+	xtension string // "" for the primary HDU
+	bitpix   int64
+	naxes    []int64
+	pcount   int64
+	gcount   int64
+	tfields  int64
+}
+
+func (v *verifier) errorf(card, format string, args ...interface{}) {
+	v.r.Issues = append(v.r.Issues, Issue{SeverityError, v.ihdu, card, fmt.Sprintf(format, args...)})
+}
+
+func (v *verifier) warnf(card, format string, args ...interface{}) {
+	v.r.Issues = append(v.r.Issues, Issue{SeverityWarning, v.ihdu, card, fmt.Sprintf(format, args...)})
+}
+
+// index returns the position (0-based) of keyword n in the current header, or -1.
+func (v *verifier) index(n string) int {
+	for i, key := range v.keys {
+		if key.name == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// integer returns the value of the integer keyword n.
+func (v *verifier) integer(n string) (int64, bool) {
+	i := v.index(n)
+	if i < 0 {
+		return 0, false
+	}
+	val, err := strconv.ParseInt(v.keys[i].value, 10, 64)
+	return val, err == nil
+}
+
+// str returns the value of the string keyword n.
+func (v *verifier) str(n string) (string, bool) {
+	i := v.index(n)
+	if i < 0 {
+		return "", false
+	}
+	val := v.keys[i].value
+	if len(val) < 2 || val[0] != '\'' || val[len(val)-1] != '\'' {
+		return "", false
+	}
+	val = strings.Replace(val[1:len(val)-1], "''", "'", -1)
+	return strings.TrimRight(val, " "), true
+}
+
+// logical returns the value of the logical keyword n.
+func (v *verifier) logical(n string) (bool, bool) {
+	i := v.index(n)
+	if i < 0 {
+		return false, false
+	}
+	switch v.keys[i].value {
+	case "T":
+		return true, true
+	case "F":
+		return false, true
+	}
+	return false, false
+}
+
+func (v *verifier) verifyHDU(i int) {
+	v.ihdu = i
+	_, err := v.f.seekHDU(i, 0)
+	if err != nil {
+		v.errorf("", "could not read HDU: %v", err)
+		return
+	}
+	v.keys, err = readRawKeys(v.f)
+	if err != nil {
+		v.errorf("", "could not read header: %v", err)
+		return
+	}
+
+	v.checkMandatory()
+	v.checkTypes()
+	switch v.xtension {
+	case "TABLE", "BINTABLE":
+		v.checkColumns()
+	}
+	v.checkBlank()
+	v.checkPadding()
+	v.checkChecksum()
+}
+
+// checkMandatory checks the order and values of the mandatory keywords of the current HDU.
+func (v *verifier) checkMandatory() {
+	var want []string
+	v.xtension = ""
+	if v.ihdu == 0 {
+		want = []string{"SIMPLE", "BITPIX", "NAXIS"}
+		if simple, ok := v.logical("SIMPLE"); ok && !simple {
+			v.warnf("SIMPLE", "file declares it does not conform to the FITS standard")
+		}
+		if v.index("XTENSION") >= 0 {
+			v.errorf("XTENSION", "keyword is not allowed in the primary HDU")
+		}
+	} else {
+		want = []string{"XTENSION", "BITPIX", "NAXIS"}
+		v.xtension, _ = v.str("XTENSION")
+		switch v.xtension {
+		case "IMAGE", "TABLE", "BINTABLE":
+		default:
+			v.warnf("XTENSION", "non-standard extension type %q", v.xtension)
+		}
+		if v.index("SIMPLE") >= 0 {
+			v.errorf("SIMPLE", "keyword is only allowed in the primary HDU")
+		}
+	}
+
+	v.bitpix, _ = v.integer("BITPIX")
+	switch v.bitpix {
+	case 8, 16, 32, 64, -32, -64:
+	default:
+		v.errorf("BITPIX", "invalid value %d", v.bitpix)
+	}
+
+	naxis, _ := v.integer("NAXIS")
+	if naxis < 0 || naxis > 999 {
+		v.errorf("NAXIS", "invalid value %d", naxis)
+		naxis = 0
+	}
+	v.naxes = make([]int64, naxis)
+	for i := range v.naxes {
+		n := fmt.Sprintf("NAXIS%d", i+1)
+		want = append(want, n)
+		v.naxes[i], _ = v.integer(n)
+		if v.naxes[i] < 0 {
+			v.errorf(n, "invalid value %d", v.naxes[i])
+			v.naxes[i] = 0
+		}
+	}
+
+	v.pcount, v.gcount, v.tfields = 0, 1, 0
+	if v.ihdu > 0 {
+		want = append(want, "PCOUNT", "GCOUNT")
+	}
+	// random groups carry their own PCOUNT and GCOUNT.
+	if groups, _ := v.logical("GROUPS"); v.ihdu > 0 || (groups && naxis > 0 && v.naxes[0] == 0) {
+		if pcount, ok := v.integer("PCOUNT"); ok {
+			v.pcount = pcount
+		}
+		if gcount, ok := v.integer("GCOUNT"); ok {
+			v.gcount = gcount
+		}
+	}
+
+	switch v.xtension {
+	case "IMAGE":
+		if v.pcount != 0 {
+			v.errorf("PCOUNT", "must be 0 for an IMAGE extension. got %d", v.pcount)
+		}
+		if v.gcount != 1 {
+			v.errorf("GCOUNT", "must be 1 for an IMAGE extension. got %d", v.gcount)
+		}
+	case "TABLE", "BINTABLE":
+		want = append(want, "TFIELDS")
+		v.tfields, _ = v.integer("TFIELDS")
+		if v.bitpix != 8 {
+			v.errorf("BITPIX", "must be 8 for a %s extension. got %d", v.xtension, v.bitpix)
+		}
+		if naxis != 2 {
+			v.errorf("NAXIS", "must be 2 for a %s extension. got %d", v.xtension, naxis)
+		}
+		if v.pcount < 0 || (v.xtension == "TABLE" && v.pcount != 0) {
+			v.errorf("PCOUNT", "invalid value %d for a %s extension", v.pcount, v.xtension)
+		}
+		if v.gcount != 1 {
+			v.errorf("GCOUNT", "must be 1 for a %s extension. got %d", v.xtension, v.gcount)
+		}
+		if v.tfields < 0 || v.tfields > 999 {
+			v.errorf("TFIELDS", "invalid value %d", v.tfields)
+			v.tfields = 0
+		}
+	}
+
+	for i, n := range want {
+		if i < len(v.keys) && v.keys[i].name == n {
+			continue
+		}
+		j := v.index(n)
+		if j < 0 {
+			v.errorf(n, "mandatory keyword is missing")
+			continue
+		}
+		v.errorf(n, "mandatory keyword is out of order: expected at position %d. got %d", i+1, j+1)
+	}
+}
+
+// checkTypes checks the value types of the reserved keywords of the current HDU.
+func (v *verifier) checkTypes() {
+	for _, key := range v.keys {
+		want, ok := g_reserved[key.name]
+		if !ok {
+			root, i := splitIndex(key.name)
+			if i == 0 {
+				continue
+			}
+			want, ok = g_reservedIndexed[root]
+			if !ok {
+				continue
+			}
+			if root == "TNULL" && v.xtension == "TABLE" {
+				want = 'C'
+			}
+		}
+
+		if key.value == "" {
+			v.errorf(key.name, "reserved keyword has no value")
+			continue
+		}
+		got, err := keyType(key.value)
+		if err != nil {
+			v.errorf(key.name, "invalid value %s: %v", key.value, err)
+			continue
+		}
+
+		switch want {
+		case 'F':
+			ok = got == 'F' || got == 'I' || got == 'T'
+		case 'I':
+			ok = got == 'I' || got == 'T'
+		default:
+			ok = got == want
+		}
+		if !ok {
+			v.errorf(key.name, "expected a %s value. got %s", typeName(want), key.value)
+		}
+	}
+}
+
+// typeName returns the name of a keyword value type, as returned by keyType.
+func typeName(t byte) string {
+	switch t {
+	case 'L':
+		return "logical"
+	case 'I':
+		return "integer"
+	case 'F':
+		return "real"
+	case 'C':
+		return "string"
+	default:
+		return "complex"
+	}
+}
+
+// checkColumns checks the consistency of the column keywords of the current table.
+func (v *verifier) checkColumns() {
+	ascii := v.xtension == "TABLE"
+	naxis1 := int64(0)
+	if len(v.naxes) > 0 {
+		naxis1 = v.naxes[0]
+	}
+
+	for _, key := range v.keys {
+		root, i := splitIndex(key.name)
+		if i == 0 {
+			continue
+		}
+		for _, n := range g_columnKeys {
+			if root == n && int64(i) > v.tfields {
+				v.warnf(key.name, "column keyword beyond TFIELDS=%d", v.tfields)
+			}
+		}
+	}
+
+	rowlen := int64(0)
+	valid := true
+	for i := 1; i <= int(v.tfields); i++ {
+		n := fmt.Sprintf("TFORM%d", i)
+		tform, ok := v.str(n)
+		if !ok {
+			v.errorf(n, "mandatory keyword is missing")
+			valid = false
+			continue
+		}
+		if ascii {
+			width, err := asciiTForm(tform)
+			if err != nil {
+				v.errorf(n, "invalid value %q: %v", tform, err)
+				continue
+			}
+			ncol := fmt.Sprintf("TBCOL%d", i)
+			bcol, ok := v.integer(ncol)
+			switch {
+			case !ok:
+				v.errorf(ncol, "mandatory keyword is missing")
+			case bcol < 1 || bcol+width-1 > naxis1:
+				v.errorf(ncol, "field of %d characters at column %d does not fit in NAXIS1=%d", width, bcol, naxis1)
+			}
+			if ndim := fmt.Sprintf("TDIM%d", i); v.index(ndim) >= 0 {
+				v.errorf(ndim, "keyword is not allowed in ASCII tables")
+			}
+			continue
+		}
+
+		code, repeat, width, err := binaryTForm(tform)
+		if err != nil {
+			v.errorf(n, "invalid value %q: %v", tform, err)
+			valid = false
+			continue
+		}
+		switch {
+		case code < 0:
+			// variable-length array descriptors.
+			size := int64(8)
+			if strings.Contains(strings.ToUpper(strings.TrimLeft(tform, "0123456789 ")), "Q") {
+				size = 16
+			}
+			rowlen += repeat * size
+		case code == TBIT:
+			rowlen += (repeat + 7) / 8
+		case code == TSTRING:
+			rowlen += repeat
+		default:
+			rowlen += repeat * width
+		}
+
+		ndim := fmt.Sprintf("TDIM%d", i)
+		tdim, ok := v.str(ndim)
+		if !ok {
+			continue
+		}
+		dims, err := parseTDim(tdim)
+		if err != nil {
+			v.errorf(ndim, "invalid value %q", tdim)
+			continue
+		}
+		if code < 0 {
+			continue
+		}
+		nelem := int64(1)
+		for _, dim := range dims {
+			nelem *= dim
+		}
+		if nelem != repeat {
+			v.errorf(ndim, "%q holds %d elements but TFORM%d=%q holds %d", tdim, nelem, i, tform, repeat)
+		}
+	}
+
+	if !ascii && valid && rowlen != naxis1 {
+		v.errorf("NAXIS1", "row length %d does not match the sum of the column widths (%d)", naxis1, rowlen)
+	}
+}
+
+// asciiTForm returns the width in characters of a field of an ASCII table, from its TFORMn value.
+func asciiTForm(tform string) (int64, error) {
+	c_tform := C.CString(tform)
+	defer C.free(unsafe.Pointer(c_tform))
+	c_code := C.int(0)
+	c_width := C.long(0)
+	c_decimals := C.int(0)
+	c_status := C.int(0)
+	C.fits_ascii_tform(c_tform, &c_code, &c_width, &c_decimals, &c_status)
+	if c_status > 0 {
+		return 0, to_err(c_status)
+	}
+	return int64(c_width), nil
+}
+
+// binaryTForm returns the type code, repeat count and element width of a column of a binary table,
+// from its TFORMn value. The type code is negative for variable-length arrays.
+func binaryTForm(tform string) (TypeCode, int64, int64, error) {
+	c_tform := C.CString(tform)
+	defer C.free(unsafe.Pointer(c_tform))
+	c_code := C.int(0)
+	c_repeat := C.LONGLONG(0)
+	c_width := C.LONGLONG(0)
+	c_status := C.int(0)
+	C.fits_binary_tformll(c_tform, &c_code, &c_repeat, &c_width, &c_status)
+	if c_status > 0 {
+		return 0, 0, 0, to_err(c_status)
+	}
+	return TypeCode(c_code), int64(c_repeat), int64(c_width), nil
+}
+
+// parseTDim parses the value of a TDIMn keyword, e.g. "(3,2)".
+func parseTDim(tdim string) ([]int64, error) {
+	tdim = strings.TrimSpace(tdim)
+	if !strings.HasPrefix(tdim, "(") || !strings.HasSuffix(tdim, ")") {
+		return nil, fmt.Errorf("cfitsio: invalid TDIM value %q", tdim)
+	}
+	var dims []int64
+	for _, tok := range strings.Split(tdim[1:len(tdim)-1], ",") {
+		dim, err := strconv.ParseInt(strings.TrimSpace(tok), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if dim < 0 {
+			return nil, fmt.Errorf("cfitsio: invalid TDIM value %q", tdim)
+		}
+		dims = append(dims, dim)
+	}
+	return dims, nil
+}
+
+// checkBlank checks that BLANK is compatible with BITPIX.
+func (v *verifier) checkBlank() {
+	if v.index("BLANK") < 0 {
+		return
+	}
+	switch v.xtension {
+	case "TABLE", "BINTABLE":
+		v.warnf("BLANK", "keyword is not used by tables (use TNULLn)")
+		return
+	}
+	if v.bitpix < 0 {
+		v.errorf("BLANK", "keyword is not allowed with floating-point data (BITPIX=%d)", v.bitpix)
+		return
+	}
+	blank, ok := v.integer("BLANK")
+	if !ok {
+		// already reported by checkTypes.
+		return
+	}
+
+	var min, max int64
+	switch v.bitpix {
+	case 8:
+		min, max = 0, math.MaxUint8
+	case 16:
+		min, max = math.MinInt16, math.MaxInt16
+	case 32:
+		min, max = math.MinInt32, math.MaxInt32
+	default:
+		return
+	}
+	if blank < min || blank > max {
+		v.errorf("BLANK", "value %d is out of range for BITPIX=%d", blank, v.bitpix)
+	}
+}
+
+// dataSize returns the size in bytes of the data unit of the current HDU, without its padding.
+func (v *verifier) dataSize() int64 {
+	if len(v.naxes) == 0 {
+		return 0
+	}
+	axes := v.naxes
+	if groups, _ := v.logical("GROUPS"); v.ihdu == 0 && groups && axes[0] == 0 {
+		// random groups: NAXIS1=0 is not part of the data size.
+		axes = axes[1:]
+	}
+	n := int64(1)
+	for _, axis := range axes {
+		n *= axis
+	}
+	bitpix := v.bitpix
+	if bitpix < 0 {
+		bitpix = -bitpix
+	}
+	return bitpix / 8 * v.gcount * (v.pcount + n)
+}
+
+// checkPadding checks the fill bytes following the END keyword and the data unit of the current HDU.
+func (v *verifier) checkPadding() {
+	c_head := C.LONGLONG(0)
+	c_data := C.LONGLONG(0)
+	c_end := C.LONGLONG(0)
+	c_status := C.int(0)
+	C.fits_get_hduaddrll(v.f.c, &c_head, &c_data, &c_end, &c_status)
+	if c_status > 0 {
+		v.errorf("", "could not locate HDU: %v", to_err(c_status))
+		return
+	}
+	head, data, end := int64(c_head), int64(c_data), int64(c_end)
+
+	// the END keyword follows the last keyword, possibly after blank records.
+	beg := head + int64(len(v.keys))*80
+	if beg > data {
+		v.errorf("", "header of %d keywords overflows the header unit", len(v.keys))
+		return
+	}
+	buf := make([]byte, data-beg)
+	err := v.f.readAt(buf, beg)
+	if err != nil {
+		v.warnf("", "could not check padding: %v", err)
+		return
+	}
+	iend := -1
+	for i := 0; i+80 <= len(buf); i += 80 {
+		if string(buf[i:i+3]) == "END" && len(bytes.TrimRight(buf[i+3:i+80], " ")) == 0 {
+			iend = i
+			break
+		}
+	}
+	switch {
+	case iend < 0:
+		v.errorf("END", "keyword not found")
+	case len(bytes.TrimRight(buf[iend+80:], " ")) != 0:
+		v.errorf("END", "header fill after the END keyword is not blank")
+	}
+
+	size := v.dataSize()
+	if data+size > end {
+		v.errorf("", "data unit of %d bytes overflows the HDU", size)
+		return
+	}
+	buf = make([]byte, end-data-size)
+	err = v.f.readAt(buf, data+size)
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		v.errorf("", "file is truncated: the last block of the data unit is incomplete")
+		return
+	case err != nil:
+		v.warnf("", "could not check padding: %v", err)
+		return
+	}
+
+	fill := byte(0)
+	if v.xtension == "TABLE" {
+		fill = ' '
+	}
+	for _, b := range buf {
+		if b != fill {
+			v.errorf("", "data fill is not made of %q bytes", fill)
+			break
+		}
+	}
+}
+
+// checkChecksum checks the CHECKSUM and DATASUM keywords of the current HDU, if any.
+func (v *verifier) checkChecksum() {
+	if v.index("CHECKSUM") < 0 && v.index("DATASUM") < 0 {
+		return
+	}
+	sum, err := verifyChecksum(v.f)
+	if err != nil {
+		v.errorf("CHECKSUM", "could not verify checksums: %v", err)
+		return
+	}
+	if sum.HDU == ChecksumBad {
+		v.errorf("CHECKSUM", "HDU checksum is incorrect")
+	}
+	if sum.Data == ChecksumBad {
+		v.errorf("DATASUM", "data checksum is incorrect")
+	}
+}
+
+// readAt reads len(buf) raw bytes of the file, starting at offset off.
+// It returns io.ErrUnexpectedEOF if the file ends before off+len(buf).
+func (f *File) readAt(buf []byte, off int64) error {
+	if len(buf) == 0 {
+		return nil
+	}
+
+	const reportEOF = 0 // CFITSIO's REPORT_EOF
+	c_status := C.int(0)
+	C.ffmbyt(f.c, C.LONGLONG(off+int64(len(buf))-1), reportEOF, &c_status)
+	if c_status == C.END_OF_FILE {
+		return io.ErrUnexpectedEOF
+	}
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	C.ffmbyt(f.c, C.LONGLONG(off), reportEOF, &c_status)
+	if c_status > 0 {
+		return to_err(c_status)
+	}
+	C.ffgbyt(f.c, C.LONGLONG(len(buf)), unsafe.Pointer(&buf[0]), &c_status)
+	return to_err(c_status)
+}
+
+// EOF
@@ -0,0 +1,212 @@
+package cfitsio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			phdu, err := NewPrimaryHDU(&f, NewHeader(nil, IMAGE_HDU, 16, []int64{3, 2}))
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+			data := []int16{1, 2, 3, 4, 5, 6}
+			err = phdu.(*PrimaryHDU).Write(&data)
+			if err != nil {
+				t.Fatalf("error writing image: %v", err)
+			}
+
+			for _, htype := range []HDUType{ASCII_TBL, BINARY_TBL} {
+				tbl, err := NewTable(
+					&f, "data",
+					[]Column{
+						{Name: "ID", Value: int32(0)},
+						{Name: "NAME", Value: ""},
+					},
+					htype,
+				)
+				if err != nil {
+					t.Fatalf("error creating table: %v", err)
+				}
+				defer tbl.Close()
+				ids := []int32{1, 2, 3}
+				err = tbl.WriteColumn(0, 0, &ids)
+				if err != nil {
+					t.Fatalf("error writing column: %v", err)
+				}
+			}
+
+			err = f.WriteChecksums()
+			if err != nil {
+				t.Fatalf("error writing checksums: %v", err)
+			}
+		},
+		// verify
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			report := Verify(&f)
+			if report.NumHDUs != 3 {
+				t.Fatalf("expected 3 verified HDUs. got %d", report.NumHDUs)
+			}
+			if !report.OK() {
+				t.Fatalf("expected a valid file. got %v", report.Issues)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestVerifyInvalid(t *testing.T) {
+	// header returns the header block made of the given cards.
+	header := func(cards ...string) []byte {
+		var buf bytes.Buffer
+		for _, card := range append(cards, "END") {
+			buf.WriteString(card)
+			buf.Write(bytes.Repeat([]byte(" "), 80-len(card)))
+		}
+		if n := buf.Len() % 2880; n != 0 {
+			buf.Write(bytes.Repeat([]byte(" "), 2880-n))
+		}
+		return buf.Bytes()
+	}
+
+	var raw []byte
+	raw = append(raw, header(
+		"SIMPLE  =                    T",
+		"BITPIX  =                  -32",
+		"NAXIS   =                    1",
+		"NAXIS1  =                    2",
+		"EXTEND  = 'yes'",
+		"BLANK   =                    0",
+	)...)
+	data := make([]byte, 2880)
+	data[2879] = 1 // invalid fill
+	raw = append(raw, data...)
+
+	raw = append(raw, header(
+		"XTENSION= 'BINTABLE'",
+		"BITPIX  =                    8",
+		"NAXIS   =                    2",
+		"NAXIS1  =                   16",
+		"NAXIS2  =                    1",
+		"PCOUNT  =                    0",
+		"GCOUNT  =                    1",
+		"TFIELDS =                    1",
+		"TTYPE1  = 'X'",
+		"TFORM1  = '4E'",
+		"TDIM1   = '(3,2)'",
+		"TTYPE2  = 'Y'",
+		"CHECKSUM= '1234567890ABCDEF'",
+	)...)
+	raw = append(raw, make([]byte, 2880)...)
+
+	raw = append(raw, header(
+		"XTENSION= 'IMAGE'",
+		"BITPIX  =                   16",
+		"NAXIS   =                    0",
+		"GCOUNT  =                    1",
+		"PCOUNT  =                    0",
+	)...)
+
+	f, err := OpenBytes(raw, ReadOnly)
+	if err != nil {
+		t.Fatalf("error opening file: %v", err)
+	}
+	defer f.Close()
+
+	report := Verify(&f)
+	if report.NumHDUs != 3 {
+		t.Fatalf("expected 3 verified HDUs. got %d", report.NumHDUs)
+	}
+	if report.OK() {
+		t.Fatalf("expected an invalid file")
+	}
+
+	for _, want := range []Issue{
+		{Severity: SeverityError, HDU: 0, Card: "EXTEND"},
+		{Severity: SeverityError, HDU: 0, Card: "BLANK"},
+		{Severity: SeverityError, HDU: 0, Card: ""},
+		{Severity: SeverityError, HDU: 1, Card: "TDIM1"},
+		{Severity: SeverityWarning, HDU: 1, Card: "TTYPE2"},
+		{Severity: SeverityError, HDU: 1, Card: "CHECKSUM"},
+		{Severity: SeverityError, HDU: 2, Card: "PCOUNT"},
+		{Severity: SeverityError, HDU: 2, Card: "GCOUNT"},
+	} {
+		found := false
+		for _, issue := range report.HDU(want.HDU) {
+			if issue.Severity == want.Severity && issue.Card == want.Card {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("expected a %v on card %q of hdu #%d. got %v", want.Severity, want.Card, want.HDU, report.HDU(want.HDU))
+		}
+	}
+}
+
+func TestVerifyEmptyAxis(t *testing.T) {
+	// a primary image with NAXIS1=0 is empty: it is not a random groups HDU without GROUPS=T.
+	var raw []byte
+	for _, card := range []string{
+		"SIMPLE  =                    T",
+		"BITPIX  =                    8",
+		"NAXIS   =                    2",
+		"NAXIS1  =                    0",
+		"NAXIS2  =                    3",
+		"END",
+	} {
+		raw = append(raw, card...)
+		raw = append(raw, bytes.Repeat([]byte(" "), 80-len(card))...)
+	}
+	raw = append(raw, bytes.Repeat([]byte(" "), 2880-len(raw))...)
+
+	f, err := OpenBytes(raw, ReadOnly)
+	if err != nil {
+		t.Fatalf("error opening file: %v", err)
+	}
+	defer f.Close()
+
+	report := Verify(&f)
+	if !report.OK() {
+		t.Fatalf("expected a valid file. got %v", report.Issues)
+	}
+}
+
+// EOF
@@ -0,0 +1,372 @@
+package cfitsio
+
+// #include "go-cfitsio.h"
+// #include "go-cfitsio-utils.h"
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// WCS describes the celestial World Coordinate System of a 2-dimensional image or of a pair of table columns,
+// in the classic AIPS convention used by CFITSIO:
+// pixel offsets from the reference pixel are scaled by CDelt, rotated by Rot and projected around CRVal.
+//
+// Supported projections are -SIN, -TAN, -ARC, -NCP, -GLS, -MER, -AIT, -STG and -CAR.
+// Axis types without a projection (e.g. "X", "LINEAR") describe a linear transformation.
+type WCS struct {
+	CType [2]string  // axis types, e.g. "RA---TAN" and "DEC--TAN"
+	CUnit [2]string  // axis units, if any
+	CRPix [2]float64 // reference pixel (1-based)
+	CRVal [2]float64 // world coordinates of the reference pixel (degrees for celestial axes)
+	CDelt [2]float64 // world coordinate increments per pixel
+	Rot   float64    // rotation angle of the second axis (degrees)
+}
+
+// NewWCS returns the WCS described by the keywords of hdr:
+// CTYPEi, CRPIXi, CRVALi and either CDELTi (with CROTA2 or a PCi_j matrix) or a CDi_j matrix.
+// Skewed PC and CD matrices can not be represented and are approximated.
+// It returns NO_WCS_KEY if hdr holds no coordinate increments.
+func NewWCS(hdr Header) (*WCS, error) {
+	var err error
+	get := func(n string, def float64) float64 {
+		card := hdr.Get(n)
+		if card == nil {
+			return def
+		}
+		v, e := toFloat64(card.Value)
+		if e != nil && err == nil {
+			err = fmt.Errorf("cfitsio: invalid %s value: %v", n, e)
+		}
+		return v
+	}
+	str := func(n string) string {
+		card := hdr.Get(n)
+		if card == nil {
+			return ""
+		}
+		v, _ := card.Value.(string)
+		return strings.TrimSpace(v)
+	}
+
+	w := &WCS{
+		CType: [2]string{str("CTYPE1"), str("CTYPE2")},
+		CUnit: [2]string{str("CUNIT1"), str("CUNIT2")},
+		CRPix: [2]float64{get("CRPIX1", 0), get("CRPIX2", 0)},
+		CRVal: [2]float64{get("CRVAL1", 0), get("CRVAL2", 0)},
+	}
+
+	switch {
+	case hdr.Get("CDELT1") != nil || hdr.Get("CDELT2") != nil:
+		cdelt1, cdelt2 := get("CDELT1", 1), get("CDELT2", 1)
+		if hdr.Get("PC1_1") != nil || hdr.Get("PC1_2") != nil || hdr.Get("PC2_1") != nil || hdr.Get("PC2_2") != nil {
+			w.setCD(
+				cdelt1*get("PC1_1", 1), cdelt1*get("PC1_2", 0),
+				cdelt2*get("PC2_1", 0), cdelt2*get("PC2_2", 1),
+			)
+			break
+		}
+		w.CDelt = [2]float64{cdelt1, cdelt2}
+		w.Rot = get("CROTA2", 0)
+
+	case hdr.Get("CD1_1") != nil || hdr.Get("CD2_2") != nil:
+		w.setCD(get("CD1_1", 0), get("CD1_2", 0), get("CD2_1", 0), get("CD2_2", 0))
+
+	default:
+		return nil, NO_WCS_KEY
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// setCD sets the increments and rotation from a CD matrix, the same way CFITSIO does.
+func (w *WCS) setCD(cd11, cd12, cd21, cd22 float64) {
+	phia := math.Atan2(cd21, cd11)
+	phib := math.Atan2(-cd12, cd22)
+	phia, phib = math.Min(phia, phib), math.Max(phia, phib)
+	// there is a possible 180 degrees ambiguity in the angles.
+	if phib-phia > math.Pi/2 {
+		phia += math.Pi
+	}
+	phi := 0.5 * (phia + phib)
+
+	xinc := cd11 / math.Cos(phi)
+	yinc := cd22 / math.Cos(phi)
+	rot := phi * 180 / math.Pi
+	// common usage is to have a positive yinc value.
+	if yinc < 0 {
+		xinc = -xinc
+		yinc = -yinc
+		rot -= 180
+	}
+	w.CDelt = [2]float64{xinc, yinc}
+	w.Rot = rot
+}
+
+// Projection returns the projection code of this WCS (e.g. "-TAN"), or "" for a linear WCS.
+// Celestial axis types are made of a 4-character coordinate name and a projection code,
+// padded with '-' (e.g. "RA---TAN" and "DEC--TAN").
+// It returns an error if the two axes have different projections,
+// or if they describe a distortion (e.g. "RA---TAN-SIP" or "RA---TPV"), which is not supported.
+func (w *WCS) Projection() (string, error) {
+	proj := axisProjection(w.CType[0])
+	if p := axisProjection(w.CType[1]); p != proj {
+		return "", fmt.Errorf("cfitsio: axes %q and %q have different projections", w.CType[0], w.CType[1])
+	}
+	switch {
+	case len(proj) > 4:
+		return "", fmt.Errorf("cfitsio: unsupported distortion %q of projection %q", proj[4:], proj[:4])
+	case proj == "-TPV":
+		// TAN with a polynomial distortion described by PVi_j keywords.
+		return "", fmt.Errorf("cfitsio: unsupported distortion of projection %q", proj)
+	}
+	return proj, nil
+}
+
+// axisProjection returns the projection code of the axis type ctype, with its distortion suffix if any
+// (e.g. "-TAN" for "RA---TAN", "-TAN-SIP" for "RA---TAN-SIP"), or "" for a linear axis.
+func axisProjection(ctype string) string {
+	ctype = strings.TrimRight(ctype, " ")
+	if len(ctype) < 8 || ctype[4] != '-' {
+		return ""
+	}
+	return ctype[4:]
+}
+
+// PixelToWorld returns the world coordinates of the pixel (x, y).
+// Pixel coordinates are 1-based: the center of the first pixel is (1, 1).
+func (w *WCS) PixelToWorld(x, y float64) (float64, float64, error) {
+	proj, err := w.Projection()
+	if err != nil {
+		return 0, 0, err
+	}
+	if proj == "" {
+		dx := (x - w.CRPix[0]) * w.CDelt[0]
+		dy := (y - w.CRPix[1]) * w.CDelt[1]
+		sin, cos := math.Sincos(w.Rot * math.Pi / 180)
+		return w.CRVal[0] + dx*cos - dy*sin, w.CRVal[1] + dy*cos + dx*sin, nil
+	}
+
+	c_type := C.CString(proj)
+	defer C.free(unsafe.Pointer(c_type))
+	c_xpos := C.double(0)
+	c_ypos := C.double(0)
+	c_status := C.int(0)
+	C.fits_pix_to_world(
+		C.double(x), C.double(y),
+		C.double(w.CRVal[0]), C.double(w.CRVal[1]),
+		C.double(w.CRPix[0]), C.double(w.CRPix[1]),
+		C.double(w.CDelt[0]), C.double(w.CDelt[1]),
+		C.double(w.Rot), c_type, &c_xpos, &c_ypos, &c_status,
+	)
+	if c_status > 0 {
+		return 0, 0, to_err(c_status)
+	}
+	return float64(c_xpos), float64(c_ypos), nil
+}
+
+// WorldToPixel returns the 1-based pixel coordinates of the world coordinates (xpos, ypos).
+func (w *WCS) WorldToPixel(xpos, ypos float64) (float64, float64, error) {
+	proj, err := w.Projection()
+	if err != nil {
+		return 0, 0, err
+	}
+	if proj == "" {
+		if w.CDelt[0] == 0 || w.CDelt[1] == 0 {
+			return 0, 0, BAD_WCS_VAL
+		}
+		dx := xpos - w.CRVal[0]
+		dy := ypos - w.CRVal[1]
+		sin, cos := math.Sincos(w.Rot * math.Pi / 180)
+		x := (dx*cos + dy*sin) / w.CDelt[0]
+		y := (dy*cos - dx*sin) / w.CDelt[1]
+		return x + w.CRPix[0], y + w.CRPix[1], nil
+	}
+
+	c_type := C.CString(proj)
+	defer C.free(unsafe.Pointer(c_type))
+	c_xpix := C.double(0)
+	c_ypix := C.double(0)
+	c_status := C.int(0)
+	C.fits_world_to_pix(
+		C.double(xpos), C.double(ypos),
+		C.double(w.CRVal[0]), C.double(w.CRVal[1]),
+		C.double(w.CRPix[0]), C.double(w.CRPix[1]),
+		C.double(w.CDelt[0]), C.double(w.CDelt[1]),
+		C.double(w.Rot), c_type, &c_xpix, &c_ypix, &c_status,
+	)
+	if c_status > 0 {
+		return 0, 0, to_err(c_status)
+	}
+	return float64(c_xpix), float64(c_ypix), nil
+}
+
+// Cards returns the header Cards describing this WCS.
+// They describe the coordinate increments with CDELTi and CROTA2, so they must not be
+// mixed with PCi_j or CDi_j Cards.
+func (w *WCS) Cards() []Card {
+	cards := []Card{
+		{Name: "CTYPE1", Value: w.CType[0], Comment: "axis type"},
+		{Name: "CTYPE2", Value: w.CType[1], Comment: "axis type"},
+		{Name: "CRPIX1", Value: w.CRPix[0], Comment: "reference pixel"},
+		{Name: "CRPIX2", Value: w.CRPix[1], Comment: "reference pixel"},
+		{Name: "CRVAL1", Value: w.CRVal[0], Comment: "coordinate value at reference pixel"},
+		{Name: "CRVAL2", Value: w.CRVal[1], Comment: "coordinate value at reference pixel"},
+		{Name: "CDELT1", Value: w.CDelt[0], Comment: "coordinate increment per pixel"},
+		{Name: "CDELT2", Value: w.CDelt[1], Comment: "coordinate increment per pixel"},
+		{Name: "CROTA2", Value: w.Rot, Comment: "rotation angle (degrees)"},
+	}
+	for i, unit := range w.CUnit {
+		if unit != "" {
+			cards = append(cards, Card{Name: fmt.Sprintf("CUNIT%d", i+1), Value: unit, Comment: "axis unit"})
+		}
+	}
+	return cards
+}
+
+// wcsMatrixKeys are the keywords of the PC and CD matrices, superseded by the Cards of a WCS.
+var wcsMatrixKeys = []string{
+	"PC1_1", "PC1_2", "PC2_1", "PC2_2",
+	"CD1_1", "CD1_2", "CD2_1", "CD2_2",
+}
+
+// WriteHeader sets the Cards describing this WCS into hdr.
+// The PCi_j and CDi_j Cards of hdr, if any, are removed.
+func (w *WCS) WriteHeader(hdr *Header) {
+	for _, n := range wcsMatrixKeys {
+		hdr.Delete(n)
+	}
+	for _, card := range w.Cards() {
+		hdr.Set(card.Name, card.Value, card.Comment)
+	}
+}
+
+// WCS returns the World Coordinate System of this image.
+func (hdu *ImageHDU) WCS() (*WCS, error) {
+	return NewWCS(hdu.header)
+}
+
+// SetWCS writes the keywords describing w into the header of this image, in the file and in the cached Header.
+// The PCi_j and CDi_j keywords of the header, if any, are deleted.
+func (hdu *ImageHDU) SetWCS(w *WCS) error {
+	return editHeader(hdu.f, hdu, func(f *File) error {
+		for _, n := range wcsMatrixKeys {
+			if hdu.header.Get(n) == nil {
+				continue
+			}
+			err := deleteKey(f, n)
+			if err != nil {
+				return err
+			}
+		}
+		return writeCards(f, w.Cards())
+	})
+}
+
+// ColumnWCS returns the World Coordinate System of the pair of columns xcol and ycol,
+// as described by their TCTYPn, TCRPXn, TCRVLn, TCDLTn and TCROTn keywords.
+// The column values are the pixel coordinates.
+func (hdu *Table) ColumnWCS(xcol, ycol string) (*WCS, error) {
+	ix := hdu.Index(xcol)
+	if ix < 0 {
+		return nil, fmt.Errorf("cfitsio: no such column %q", xcol)
+	}
+	iy := hdu.Index(ycol)
+	if iy < 0 {
+		return nil, fmt.Errorf("cfitsio: no such column %q", ycol)
+	}
+
+	err := hdu.seekHDU()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		c_xrefval C.double
+		c_yrefval C.double
+		c_xrefpix C.double
+		c_yrefpix C.double
+		c_xinc    C.double
+		c_yinc    C.double
+		c_rot     C.double
+		c_type    [C.FLEN_VALUE]C.char
+	)
+	c_status := C.int(0)
+	C.fits_read_tbl_coord(
+		hdu.f.c, C.int(ix+1), C.int(iy+1),
+		&c_xrefval, &c_yrefval, &c_xrefpix, &c_yrefpix, &c_xinc, &c_yinc, &c_rot,
+		&c_type[0], &c_status,
+	)
+	if c_status > 0 {
+		return nil, to_err(c_status)
+	}
+
+	str := func(n string, icol int) string {
+		card := hdu.header.Get(fmt.Sprintf("%s%d", n, icol+1))
+		if card == nil {
+			return ""
+		}
+		v, _ := card.Value.(string)
+		return strings.TrimSpace(v)
+	}
+	return &WCS{
+		CType: [2]string{str("TCTYP", ix), str("TCTYP", iy)},
+		CUnit: [2]string{str("TCUNI", ix), str("TCUNI", iy)},
+		CRPix: [2]float64{float64(c_xrefpix), float64(c_yrefpix)},
+		CRVal: [2]float64{float64(c_xrefval), float64(c_yrefval)},
+		CDelt: [2]float64{float64(c_xinc), float64(c_yinc)},
+		Rot:   float64(c_rot),
+	}, nil
+}
+
+// ScanWorld computes the world coordinates (e.g. RA and Dec) of the current row into xpos and ypos,
+// from the values of the columns xcol and ycol and their WCS keywords.
+func (rows *Rows) ScanWorld(xcol, ycol string, xpos, ypos *float64) error {
+	var err error
+	defer func() {
+		rows.err = err
+	}()
+
+	key := [2]string{xcol, ycol}
+	w, ok := rows.wcs[key]
+	if !ok {
+		w, err = rows.table.ColumnWCS(xcol, ycol)
+		if err != nil {
+			return err
+		}
+		if rows.wcs == nil {
+			rows.wcs = make(map[[2]string]*WCS)
+		}
+		rows.wcs[key] = w
+	}
+
+	var x, y float64
+	ix := rows.table.Index(xcol)
+	err = rows.table.seekColumn(ix)
+	if err != nil {
+		return err
+	}
+	err = rows.table.cols[ix].read(rows.table.f, ix, rows.cur, &x)
+	if err != nil {
+		return err
+	}
+	iy := rows.table.Index(ycol)
+	err = rows.table.seekColumn(iy)
+	if err != nil {
+		return err
+	}
+	err = rows.table.cols[iy].read(rows.table.f, iy, rows.cur, &y)
+	if err != nil {
+		return err
+	}
+
+	*xpos, *ypos, err = w.PixelToWorld(x, y)
+	return err
+}
+
+// EOF
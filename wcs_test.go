@@ -0,0 +1,373 @@
+package cfitsio
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+)
+
+func TestWCS(t *testing.T) {
+	const eps = 1e-6
+	near := func(a, b float64) bool {
+		return math.Abs(a-b) < eps
+	}
+
+	for _, proj := range []string{"-TAN", "-SIN", "-CAR", "-AIT"} {
+		hdr := NewHeader(
+			[]Card{
+				{Name: "CTYPE1", Value: "RA--" + proj},
+				{Name: "CTYPE2", Value: "DEC-" + proj},
+				{Name: "CRPIX1", Value: 50.5},
+				{Name: "CRPIX2", Value: 50.5},
+				{Name: "CRVAL1", Value: 10.0},
+				{Name: "CRVAL2", Value: 20.0},
+				{Name: "CDELT1", Value: -0.001},
+				{Name: "CDELT2", Value: 0.001},
+			},
+			IMAGE_HDU, -32, []int64{100, 100},
+		)
+		w, err := NewWCS(hdr)
+		if err != nil {
+			t.Fatalf("%s: error parsing WCS: %v", proj, err)
+		}
+		p, err := w.Projection()
+		if err != nil {
+			t.Fatalf("%s: error reading projection: %v", proj, err)
+		}
+		if p != proj {
+			t.Fatalf("%s: expected projection %q. got %q", proj, proj, p)
+		}
+
+		ra, dec, err := w.PixelToWorld(50.5, 50.5)
+		if err != nil {
+			t.Fatalf("%s: error converting pixel: %v", proj, err)
+		}
+		if !near(ra, 10) || !near(dec, 20) {
+			t.Fatalf("%s: expected reference pixel at (10, 20). got (%v, %v)", proj, ra, dec)
+		}
+
+		for _, pix := range [][2]float64{{1, 1}, {100, 1}, {25.25, 75.75}} {
+			ra, dec, err := w.PixelToWorld(pix[0], pix[1])
+			if err != nil {
+				t.Fatalf("%s: error converting pixel %v: %v", proj, pix, err)
+			}
+			x, y, err := w.WorldToPixel(ra, dec)
+			if err != nil {
+				t.Fatalf("%s: error converting world coordinates (%v, %v): %v", proj, ra, dec, err)
+			}
+			if !near(x, pix[0]) || !near(y, pix[1]) {
+				t.Fatalf("%s: expected pixel %v. got (%v, %v)", proj, pix, x, y)
+			}
+		}
+	}
+
+	// a rotated CD matrix.
+	sin, cos := math.Sincos(30 * math.Pi / 180)
+	hdr := NewHeader(
+		[]Card{
+			{Name: "CTYPE1", Value: "RA---TAN"},
+			{Name: "CTYPE2", Value: "DEC--TAN"},
+			{Name: "CRPIX1", Value: 1.0},
+			{Name: "CRPIX2", Value: 1.0},
+			{Name: "CRVAL1", Value: 180.0},
+			{Name: "CRVAL2", Value: -45.0},
+			{Name: "CD1_1", Value: -0.001 * cos},
+			{Name: "CD1_2", Value: -0.001 * sin},
+			{Name: "CD2_1", Value: -0.001 * sin},
+			{Name: "CD2_2", Value: 0.001 * cos},
+		},
+		IMAGE_HDU, -32, []int64{10, 10},
+	)
+	w, err := NewWCS(hdr)
+	if err != nil {
+		t.Fatalf("error parsing CD matrix: %v", err)
+	}
+	if !near(w.CDelt[0], -0.001) || !near(w.CDelt[1], 0.001) || !near(w.Rot, 30) {
+		t.Fatalf("expected CDELT=(-0.001, 0.001) and rotation=30. got %v and %v", w.CDelt, w.Rot)
+	}
+
+	// a linear WCS.
+	hdr = NewHeader(
+		[]Card{
+			{Name: "CTYPE1", Value: "X"},
+			{Name: "CTYPE2", Value: "Y"},
+			{Name: "CRPIX1", Value: 1.0},
+			{Name: "CRPIX2", Value: 1.0},
+			{Name: "CRVAL1", Value: 100.0},
+			{Name: "CRVAL2", Value: 200.0},
+			{Name: "CDELT1", Value: 2.0},
+			{Name: "CDELT2", Value: 0.5},
+			{Name: "CROTA2", Value: 90.0},
+		},
+		IMAGE_HDU, -32, []int64{10, 10},
+	)
+	w, err = NewWCS(hdr)
+	if err != nil {
+		t.Fatalf("error parsing linear WCS: %v", err)
+	}
+	x, y, err := w.PixelToWorld(2, 3)
+	if err != nil {
+		t.Fatalf("error converting pixel: %v", err)
+	}
+	if !near(x, 99) || !near(y, 202) {
+		t.Fatalf("expected (99, 202). got (%v, %v)", x, y)
+	}
+	px, py, err := w.WorldToPixel(x, y)
+	if err != nil {
+		t.Fatalf("error converting world coordinates: %v", err)
+	}
+	if !near(px, 2) || !near(py, 3) {
+		t.Fatalf("expected (2, 3). got (%v, %v)", px, py)
+	}
+
+	// a spectral axis type is not a projection.
+	w = &WCS{CType: [2]string{"WAVELENGTH", "Y"}}
+	if p, err := w.Projection(); err != nil || p != "" {
+		t.Fatalf("expected no projection. got %q (err=%v)", p, err)
+	}
+
+	// axes with different projections, and distortions, are not supported.
+	for _, ctype := range [][2]string{
+		{"RA---TAN", "DEC--SIN"},
+		{"RA---TAN", "Y"},
+		{"RA---TAN-SIP", "DEC--TAN-SIP"},
+		{"RA---TPV", "DEC--TPV"},
+	} {
+		w = &WCS{CType: ctype, CDelt: [2]float64{1, 1}}
+		if p, err := w.Projection(); err == nil {
+			t.Fatalf("%v: expected an error. got projection %q", ctype, p)
+		}
+	}
+
+	_, err = NewWCS(NewDefaultHeader())
+	if err != NO_WCS_KEY {
+		t.Fatalf("expected NO_WCS_KEY. got %v", err)
+	}
+
+	_, err = NewWCS(NewHeader(
+		[]Card{
+			{Name: "CRPIX1", Value: "one"},
+			{Name: "CDELT1", Value: 1.0},
+		},
+		IMAGE_HDU, -32, []int64{10, 10},
+	))
+	if err == nil {
+		t.Fatalf("expected an error parsing a non-numeric CRPIX1")
+	}
+}
+
+func TestImageWCS(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	want := WCS{
+		CType: [2]string{"RA---TAN", "DEC--TAN"},
+		CUnit: [2]string{"deg", "deg"},
+		CRPix: [2]float64{5.5, 5.5},
+		CRVal: [2]float64{83.6, 22.0},
+		CDelt: [2]float64{-0.01, 0.01},
+		Rot:   12,
+	}
+
+	fname := "new.fits"
+	for _, fct := range []func(){
+		// create
+		func() {
+			f, err := Create(fname)
+			if err != nil {
+				t.Fatalf("error creating new file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			// a stale PC matrix, superseded by the WCS.
+			pc := []Card{
+				{Name: "PC1_1", Value: 0.0},
+				{Name: "PC1_2", Value: 1.0},
+				{Name: "PC2_1", Value: -1.0},
+				{Name: "PC2_2", Value: 0.0},
+			}
+
+			hdr := NewHeader(pc, IMAGE_HDU, -32, []int64{10, 10})
+			want.WriteHeader(&hdr)
+			if card := hdr.Get("CROTA2"); card == nil || card.Value != 12.0 {
+				t.Fatalf("expected CROTA2=12 in header. got %v", card)
+			}
+			if hdr.Get("PC1_1") != nil {
+				t.Fatalf("expected no PC1_1 in header")
+			}
+
+			phdu, err := NewPrimaryHDU(&f, NewHeader(pc, IMAGE_HDU, -32, []int64{10, 10}))
+			if err != nil {
+				t.Fatalf("error creating PHDU: %v", err)
+			}
+			defer phdu.Close()
+
+			img := &phdu.(*PrimaryHDU).ImageHDU
+			_, err = img.WCS()
+			if err != NO_WCS_KEY {
+				t.Fatalf("expected NO_WCS_KEY. got %v", err)
+			}
+			err = img.SetWCS(&want)
+			if err != nil {
+				t.Fatalf("error writing WCS: %v", err)
+			}
+			hdr = img.Header()
+			if hdr.Get("CTYPE1") == nil {
+				t.Fatalf("expected CTYPE1 in cached header")
+			}
+			if hdr.Get("PC1_1") != nil {
+				t.Fatalf("expected no PC1_1 in cached header")
+			}
+		},
+		// read-back
+		func() {
+			f, err := Open(fname, ReadOnly)
+			if err != nil {
+				t.Fatalf("error opening file [%v]: %v", fname, err)
+			}
+			defer f.Close()
+
+			w, err := f.HDU(0).(*PrimaryHDU).WCS()
+			if err != nil {
+				t.Fatalf("error reading WCS: %v", err)
+			}
+			if *w != want {
+				t.Fatalf("expected WCS %v. got %v", want, *w)
+			}
+		},
+	} {
+		fct()
+	}
+}
+
+func TestTableColumnWCS(t *testing.T) {
+	curdir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.Chdir(curdir)
+
+	workdir, err := ioutil.TempDir("", "go-cfitsio-test-")
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer os.RemoveAll(workdir)
+
+	err = os.Chdir(workdir)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	xs := []float64{4096.5, 4000, 4200.25}
+	ys := []float64{4096.5, 4100, 3900.75}
+	ref := WCS{
+		CType: [2]string{"RA---TAN", "DEC--TAN"},
+		CUnit: [2]string{"deg", "deg"},
+		CRPix: [2]float64{4096.5, 4096.5},
+		CRVal: [2]float64{83.63, 22.01},
+		CDelt: [2]float64{-0.000137, 0.000137},
+	}
+
+	f, err := Create("events.fits")
+	if err != nil {
+		t.Fatalf("error creating new file: %v", err)
+	}
+	defer f.Close()
+
+	phdu, err := NewPrimaryHDU(&f, NewDefaultHeader())
+	if err != nil {
+		t.Fatalf("error creating PHDU: %v", err)
+	}
+	defer phdu.Close()
+
+	tbl, err := NewTable(
+		&f, "events",
+		[]Column{
+			{Name: "TIME", Value: float64(0)},
+			{Name: "X", Value: float64(0)},
+			{Name: "Y", Value: float64(0)},
+		},
+		BINARY_TBL,
+		Card{Name: "TCTYP2", Value: ref.CType[0]},
+		Card{Name: "TCUNI2", Value: ref.CUnit[0]},
+		Card{Name: "TCRPX2", Value: ref.CRPix[0]},
+		Card{Name: "TCRVL2", Value: ref.CRVal[0]},
+		Card{Name: "TCDLT2", Value: ref.CDelt[0]},
+		Card{Name: "TCTYP3", Value: ref.CType[1]},
+		Card{Name: "TCUNI3", Value: ref.CUnit[1]},
+		Card{Name: "TCRPX3", Value: ref.CRPix[1]},
+		Card{Name: "TCRVL3", Value: ref.CRVal[1]},
+		Card{Name: "TCDLT3", Value: ref.CDelt[1]},
+	)
+	if err != nil {
+		t.Fatalf("error creating table: %v", err)
+	}
+	defer tbl.Close()
+
+	for i, v := range [][]float64{xs, ys} {
+		err = tbl.WriteColumn(i+1, 0, &v)
+		if err != nil {
+			t.Fatalf("error writing column: %v", err)
+		}
+	}
+
+	w, err := tbl.ColumnWCS("X", "Y")
+	if err != nil {
+		t.Fatalf("error reading column WCS: %v", err)
+	}
+	if *w != ref {
+		t.Fatalf("expected WCS %v. got %v", ref, *w)
+	}
+
+	_, err = tbl.ColumnWCS("X", "NOPE")
+	if err == nil {
+		t.Fatalf("expected an error reading the WCS of a missing column")
+	}
+	_, err = tbl.ColumnWCS("TIME", "Y")
+	if err == nil {
+		t.Fatalf("expected an error reading the WCS of a column without WCS keywords")
+	}
+
+	rows, err := tbl.Read(0, tbl.NumRows())
+	if err != nil {
+		t.Fatalf("error reading rows: %v", err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		var ra, dec float64
+		err = rows.ScanWorld("X", "Y", &ra, &dec)
+		if err != nil {
+			t.Fatalf("error scanning row [%v]: %v", i, err)
+		}
+		wra, wdec, err := ref.PixelToWorld(xs[i], ys[i])
+		if err != nil {
+			t.Fatalf("error converting pixel: %v", err)
+		}
+		if ra != wra || dec != wdec {
+			t.Fatalf("row %d: expected (%v, %v). got (%v, %v)", i, wra, wdec, ra, dec)
+		}
+		i++
+	}
+	if i != len(xs) {
+		t.Fatalf("expected %d rows. got %d", len(xs), i)
+	}
+}
+
+// EOF